@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -18,7 +19,7 @@ func main() {
 		Long:  "mseep is a fast TUI/CLI to manage MCP servers across clients (Claude, Cursor, etc.).",
 	}
 
-	root.AddCommand(cmdTUI(), cmdEnable(), cmdDisable(), cmdToggle(), cmdStatus(), cmdHealth(), cmdApply(), cmdProfiles())
+	root.AddCommand(cmdTUI(), cmdEnable(), cmdDisable(), cmdToggle(), cmdRename(), cmdStatus(), cmdHealth(), cmdLogs(), cmdInspect(), cmdPin(), cmdUnpin(), cmdScan(), cmdDoctor(), cmdValidate(), cmdSchema(), cmdApply(), cmdReconcile(), cmdProfiles(), cmdUse(), cmdHook())
 
 	if err := root.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -39,17 +40,20 @@ func cmdTUI() *cobra.Command {
 
 func cmdEnable() *cobra.Command {
 	var client, yes string
+	var forDuration time.Duration
 	cmd := &cobra.Command{
 		Use:   "enable <query>",
 		Short: "Enable server(s) by fuzzy query",
+		Long:  "Enable server(s) by fuzzy query. With --for the server is enabled temporarily; 'mseep reconcile' disables it once the time is up.",
 		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			query := args[0]
-			return cmdEnableDisableToggle("enable", query, client, yes == "true")
+			return cmdEnableFor(query, client, yes == "true", forDuration)
 		},
 	}
 	cmd.Flags().StringVar(&client, "client", "", "Target client (e.g., claude, cursor)")
 	cmd.Flags().StringVar(&yes, "yes", "false", "Assume yes; no prompt if ambiguous")
+	cmd.Flags().DurationVar(&forDuration, "for", 0, "Enable only for this long, e.g. 2h or 30m")
 	return cmd
 }
 
@@ -85,6 +89,20 @@ func cmdToggle() *cobra.Command {
 	return cmd
 }
 
+func cmdRename() *cobra.Command {
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "rename <query> <new-name>",
+		Short: "Rename a server, updating the profiles that list it",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRename(args[0], args[1], yes)
+		},
+	}
+	cmd.Flags().BoolVar(&yes, "yes", false, "Assume yes; no prompt if ambiguous, apply to clients without prompting")
+	return cmd
+}
+
 func cmdStatus() *cobra.Command {
 	var client string
 	var jsonOut bool
@@ -102,17 +120,201 @@ func cmdStatus() *cobra.Command {
 
 func cmdHealth() *cobra.Command {
 	var client, server string
-	var fix bool
+	var fix, watch bool
+	var interval time.Duration
+	var hook, format string
+	var concurrency int
 	cmd := &cobra.Command{
 		Use:   "health",
 		Short: "Run health checks (manual, opt-in)",
+		Long:  "Run health checks (manual, opt-in).\n\nExit codes: 0 all healthy, 2 unhealthy or protocol error, 3 timeout, 4 misconfigured check, 5 tool pins could not be verified, 6 pinned tools changed, 1 mseep error.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runHealth(client, server, fix)
+			if watch {
+				if fix {
+					return fmt.Errorf("--fix cannot be used with --watch")
+				}
+				if format != "table" {
+					return fmt.Errorf("--format cannot be used with --watch")
+				}
+				return runHealthWatch(client, server, interval, hook, concurrency)
+			}
+			return runHealth(client, server, fix, format, concurrency)
 		},
 	}
-	cmd.Flags().StringVar(&client, "client", "", "Target client (empty=all)")
+	cmd.Flags().StringVar(&client, "client", "", "Check this client's servers, launched in its environment (empty=current shell)")
 	cmd.Flags().StringVar(&server, "server", "", "Limit to one server by query")
-	cmd.Flags().BoolVar(&fix, "fix", false, "Auto-disable failing servers")
+	cmd.Flags().BoolVar(&fix, "fix", false, "Apply the failure policy to every server and disable servers whose pinned tools changed")
+	cmd.Flags().StringVar(&format, "format", "table", "Output format: table, json, junit, sarif or prom")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 10, "Maximum servers checked at once")
+	cmd.Flags().BoolVar(&watch, "watch", false, "Re-run checks on an interval and show a live table (not with --fix or --format)")
+	cmd.Flags().DurationVar(&interval, "interval", 60*time.Second, "Time between checks in watch mode")
+	cmd.Flags().StringVar(&hook, "on-unhealthy", "", "Shell command to run when a server goes from healthy to unhealthy (default rings the bell)")
+
+	var historyServer, since string
+	var historyJSON bool
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "Summarize recorded health checks per server",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHealthHistory(historyServer, since, historyJSON)
+		},
+	}
+	historyCmd.Flags().StringVar(&historyServer, "server", "", "Limit to servers matching query")
+	historyCmd.Flags().StringVar(&since, "since", "7d", "Lookback window (e.g. 7d, 12h)")
+	historyCmd.Flags().BoolVar(&historyJSON, "json", false, "Output JSON")
+
+	cmd.AddCommand(historyCmd)
+	return cmd
+}
+
+func cmdLogs() *cobra.Command {
+	var lines int
+	var follow, jsonOut bool
+	cmd := &cobra.Command{
+		Use:   "logs <query>",
+		Short: "Show a server's captured health check output and client logs",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLogs(args[0], lines, follow, jsonOut)
+		},
+	}
+	cmd.Flags().IntVarP(&lines, "lines", "n", 50, "Number of lines to show from each log")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing lines appended to client logs")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output JSON")
+	return cmd
+}
+
+func cmdInspect() *cobra.Command {
+	var cached, jsonOut bool
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "inspect <query>",
+		Short: "List a server's tools, prompts and resources",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInspect(args[0], cached, timeout, jsonOut)
+		},
+	}
+	cmd.Flags().BoolVar(&cached, "cached", false, "Show the last cached inventory without launching the server")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Time allowed to launch and query the server")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output JSON")
+	return cmd
+}
+
+func cmdPin() *cobra.Command {
+	var yes bool
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "pin <query>",
+		Short: "Approve a server's current tool definitions",
+		Long:  "Fetch the server's tools and, after approval, pin their names, schemas and descriptions. health and inspect flag servers whose tools later change.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPin(args[0], yes, timeout)
+		},
+	}
+	cmd.Flags().BoolVar(&yes, "yes", false, "Approve without prompting")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Time allowed to launch and query the server")
+	return cmd
+}
+
+func cmdUnpin() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unpin <query>",
+		Short: "Remove a server's approved tool pin",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUnpin(args[0])
+		},
+	}
+	return cmd
+}
+
+func cmdScan() *cobra.Command {
+	var server, format string
+	var cached bool
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Scan enabled servers' tool definitions for suspicious content",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScan(server, format, cached, timeout)
+		},
+	}
+	cmd.Flags().StringVar(&server, "server", "", "Limit to servers matching query")
+	cmd.Flags().StringVar(&format, "format", "table", "Output format: table, json or sarif")
+	cmd.Flags().BoolVar(&cached, "cached", false, "Scan cached inventories without launching servers")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Time allowed per server to launch and list tools")
+	return cmd
+}
+
+func cmdDoctor() *cobra.Command {
+	var client, server string
+	var jsonOut bool
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that server commands, runtimes and packages resolve as each client launches them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(client, server, jsonOut)
+		},
+	}
+	cmd.Flags().StringVar(&client, "client", "", "Client launch environment to simulate (empty=detected clients)")
+	cmd.Flags().StringVar(&server, "server", "", "Limit to servers matching query (default enabled servers)")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output JSON")
+	return cmd
+}
+
+func cmdReconcile() *cobra.Command {
+	var dryRun, yes bool
+	cmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Disable expired temporary enables and follow profile schedules",
+		Long: `Disable servers whose 'enable --for' time is up and enable or disable the
+servers of scheduled profiles to match their windows, then apply the result
+to detected clients. Run it from cron or a systemd timer, e.g. every 5 minutes:
+
+  */5 * * * * mseep reconcile --yes`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReconcile(dryRun, yes)
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the changes without saving or applying them")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Apply to clients without prompting")
+	return cmd
+}
+
+func cmdValidate() *cobra.Command {
+	var jsonOut bool
+	cmd := &cobra.Command{
+		Use:   "validate [file]",
+		Short: "Check a canonical config against the schema and semantic rules",
+		Long:  "Check a canonical config (default: the one mseep uses) against its JSON Schema and for duplicate names or aliases, profiles referencing unknown servers or profiles, health checks missing a url or command, and invalid schedules. Problems are printed as file:line:column. Exits 1 when any are found.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := ""
+			if len(args) == 1 {
+				path = args[0]
+			}
+			return runValidate(path, jsonOut)
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output JSON")
+	return cmd
+}
+
+func cmdSchema() *cobra.Command {
+	var write bool
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema for canonical.json",
+		Long:  "Print the JSON Schema for canonical.json. With --write, save it next to canonical.json as canonical.schema.json and point the config's $schema key at it for editor completion.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSchema(write)
+		},
+	}
+	cmd.Flags().BoolVar(&write, "write", false, "Write the schema next to canonical.json instead of printing it")
 	return cmd
 }
 
@@ -136,7 +338,7 @@ func cmdProfiles() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "profiles",
 		Short: "Manage server profiles",
-		Long:  "List, create, delete, and manage server profiles",
+		Long:  "List, create, edit, delete, and apply server profiles",
 	}
 
 	// List profiles subcommand
@@ -150,16 +352,44 @@ func cmdProfiles() *cobra.Command {
 	listCmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
 
 	// Create profile subcommand
+	var extends, exclude, tags, clients []string
+	var query, window string
+	var days []string
 	createCmd := &cobra.Command{
-		Use:   "create <name> [server1 server2 ...]",
+		Use:   "create <name> [server|tag:<tag>|query:<text> ...]",
 		Short: "Create a new profile",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Create a profile from servers and selectors, optionally extending other
+profiles and excluding servers. Selectors (--tag, --query, tag:<tag>,
+query:<text>) are matched when the profile is applied, so servers added
+later join the profile automatically. With --days or --window the profile's
+servers are enabled only inside that window; run 'mseep reconcile'
+periodically (cron, systemd timer) to enforce it.`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 			servers := args[1:]
-			return runProfilesCreate(name, servers)
+			return runProfilesCreate(name, servers, extends, exclude, tags, query, clients, days, window)
+		},
+	}
+	createCmd.Flags().StringSliceVar(&extends, "extends", nil, "Profiles to inherit servers from")
+	createCmd.Flags().StringSliceVar(&exclude, "exclude", nil, "Servers or selectors to leave out")
+	createCmd.Flags().StringSliceVar(&tags, "tag", nil, "Include every server with this tag")
+	createCmd.Flags().StringVar(&query, "query", "", "Include every server whose name, alias or tag contains this text")
+	createCmd.Flags().StringSliceVar(&clients, "client", nil, "Only apply the profile to these clients")
+	createCmd.Flags().StringSliceVar(&days, "days", nil, "Schedule days: mon..sun, weekdays or weekends")
+	createCmd.Flags().StringVar(&window, "window", "", "Schedule window in local time, e.g. 09:00-18:00")
+
+	// Show resolved profile subcommand
+	var showJSON bool
+	showCmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a profile's resolved servers and where each came from",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfilesShow(args[0], showJSON)
 		},
 	}
+	showCmd.Flags().BoolVar(&showJSON, "json", false, "Output as JSON")
 
 	// Create profile from current state
 	var fromCurrent bool
@@ -184,15 +414,159 @@ func cmdProfiles() *cobra.Command {
 	}
 
 	// Apply profile subcommand
+	var mode string
 	applyCmd := &cobra.Command{
 		Use:   "apply <name>",
 		Short: "Apply a profile",
+		Long:  "Apply a profile. Exclusive mode enables exactly the profile's servers; additive mode enables them and keeps other servers as they are. 'mseep profiles undo' restores the previous state.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfilesApply(args[0], mode)
+		},
+	}
+	applyCmd.Flags().StringVar(&mode, "mode", "exclusive", "Apply mode: exclusive or additive")
+
+	// Diff profiles subcommand
+	var diffJSON bool
+	diffCmd := &cobra.Command{
+		Use:   "diff <profile> [<other>|current]",
+		Short: "Show what changes going from another profile (default: current state) to a profile",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from := "current"
+			if len(args) == 2 {
+				from = args[1]
+			}
+			return runProfilesDiff(args[0], from, diffJSON)
+		},
+	}
+	diffCmd.Flags().BoolVar(&diffJSON, "json", false, "Output as JSON")
+
+	// Undo profile apply subcommand
+	var undoYes bool
+	undoCmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Restore the servers enabled before the last profile apply",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfilesUndo(undoYes)
+		},
+	}
+	undoCmd.Flags().BoolVar(&undoYes, "yes", false, "Apply to clients without prompting")
+
+	// Export profile subcommand
+	exportCmd := &cobra.Command{
+		Use:   "export <name>",
+		Short: "Print a profile and its server definitions as a shareable bundle",
+		Long:  "Print a profile, the profiles it extends and its servers' definitions as JSON, e.g. mseep profiles export security > security.json. Secret env vars, headers, bearer tokens, secret flag values such as --api-key, and URL passwords and tokens in args and health URLs are replaced by placeholders; env: and file: references are kept.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runProfilesApply(args[0])
+			return runProfilesExport(args[0])
 		},
 	}
 
-	cmd.AddCommand(listCmd, createCmd, saveCmd, deleteCmd, applyCmd)
+	// Import profile subcommand
+	var onConflict string
+	var importYes bool
+	importCmd := &cobra.Command{
+		Use:   "import <file|->",
+		Short: "Merge a profile bundle into the canonical config",
+		Long:  "Merge a bundle written by 'mseep profiles export'. Servers already defined identically are reused; other name collisions are resolved by --on-conflict. Missing secrets are prompted for. Imported servers, including replaced ones, start disabled.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfilesImport(args[0], onConflict, importYes)
+		},
+	}
+	importCmd.Flags().StringVar(&onConflict, "on-conflict", "prompt", "Name collisions: prompt, rename, replace or skip")
+	importCmd.Flags().BoolVar(&importYes, "yes", false, "Do not prompt; rename on collision and leave missing secrets unset")
+
+	// Rename profile subcommand
+	renameCmd := &cobra.Command{
+		Use:   "rename <name> <new-name>",
+		Short: "Rename a profile, updating profiles that extend it",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfilesRename(args[0], args[1])
+		},
+	}
+
+	// Copy profile subcommand
+	copyCmd := &cobra.Command{
+		Use:   "copy <name> <new-name>",
+		Short: "Create a profile with the same definition as another",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfilesCopy(args[0], args[1])
+		},
+	}
+
+	// Add servers subcommand
+	var addYes bool
+	addCmd := &cobra.Command{
+		Use:   "add <name> <query|tag:<tag>|query:<text>...>",
+		Short: "Add servers by fuzzy query, or selectors, to a profile",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfilesAdd(args[0], args[1:], addYes)
+		},
+	}
+	addCmd.Flags().BoolVar(&addYes, "yes", false, "Assume yes; no prompt if ambiguous")
+
+	// Remove servers subcommand
+	var removeYes bool
+	removeCmd := &cobra.Command{
+		Use:   "remove <name> <query|tag:<tag>|query:<text>...>",
+		Short: "Remove servers by fuzzy query, or selectors, from a profile",
+		Long:  "Remove servers by fuzzy query, or selectors, from a profile. A server the profile would still get from a parent profile or a selector is excluded instead.",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfilesRemove(args[0], args[1:], removeYes)
+		},
+	}
+	removeCmd.Flags().BoolVar(&removeYes, "yes", false, "Assume yes; no prompt if ambiguous")
+
+	cmd.AddCommand(listCmd, showCmd, diffCmd, createCmd, saveCmd, deleteCmd, renameCmd, copyCmd, addCmd, removeCmd, applyCmd, undoCmd, exportCmd, importCmd)
+	return cmd
+}
+
+func cmdUse() *cobra.Command {
+	var auto, off bool
+	cmd := &cobra.Command{
+		Use:   "use [dir]",
+		Short: "Apply the nearest project's .mseep.json to its project client configs",
+		Long: `Find the nearest .mseep.json at or above dir (default: current directory)
+and write the profile or servers it names to the project's client configs
+(.cursor/mcp.json, .vscode/mcp.json). The configs of the previously used
+project are restored first. Example .mseep.json:
+
+  {"profile": "security", "clients": ["cursor"]}`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if off {
+				return runUnuse()
+			}
+			dir := "."
+			if len(args) == 1 {
+				dir = args[0]
+			}
+			return runUse(dir, auto)
+		},
+	}
+	cmd.Flags().BoolVar(&auto, "auto", false, "Print only when something changes (used by the shell hook)")
+	cmd.Flags().BoolVar(&off, "off", false, "Restore the active project's configs")
+	return cmd
+}
+
+func cmdHook() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "hook <zsh|bash|fish>",
+		Short:     "Print a shell hook that runs 'mseep use' on directory change",
+		Long:      "Print a shell hook that runs 'mseep use' on directory change.\n\nAdd to your rc file, e.g. eval \"$(mseep hook zsh)\" or for fish: mseep hook fish | source",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"zsh", "bash", "fish"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHook(args[0])
+		},
+	}
 	return cmd
 }
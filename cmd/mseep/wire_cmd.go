@@ -1,11 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	
 	"mseep/internal/app"
+	"mseep/internal/config"
+	"mseep/internal/history"
 	"mseep/internal/style"
 	"mseep/internal/tui"
 )
@@ -30,6 +39,72 @@ func cmdEnableDisableToggle(mode, q, client string, yes bool) error {
 	return nil
 }
 
+func cmdEnableFor(q, client string, yes bool, d time.Duration) error {
+	if d == 0 {
+		return cmdEnableDisableToggle("enable", q, client, yes)
+	}
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.EnableFor(q, client, yes, d)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+func runReconcile(dryRun, yes bool) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	return a.Reconcile(dryRun, yes)
+}
+
+func runValidate(path string, jsonOut bool) error {
+	output, valid, err := app.Validate(path, jsonOut)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	if !valid {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func runSchema(write bool) error {
+	if !write {
+		_, err := os.Stdout.Write(config.Schema)
+		return err
+	}
+	path, err := config.DefaultPath()
+	if err != nil {
+		return err
+	}
+	dir, err := config.EnsureDir()
+	if err != nil {
+		return err
+	}
+	if err := config.WriteSchemaFile(dir); err != nil {
+		return err
+	}
+	c, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	if c.Schema == "" {
+		c.Schema = "./" + config.SchemaFile
+		if err := config.Save(path, c); err != nil {
+			return err
+		}
+	}
+	fmt.Println(style.Success("Wrote " + filepath.Join(dir, config.SchemaFile)))
+	return nil
+}
+
 func runStatus(client string, json bool) error {
 	a, err := app.LoadApp()
 	if err != nil {
@@ -42,12 +117,119 @@ func runStatus(client string, json bool) error {
 	fmt.Print(output)
 	return nil
 }
-func runHealth(client, server string, fix bool) error {
+func runHealth(client, server string, fix bool, format string, concurrency int) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, summary, err := a.Health(client, server, fix, format, concurrency)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	if code := summary.ExitCode(); code != app.ExitHealthy {
+		os.Exit(code)
+	}
+	return nil
+}
+func runHealthWatch(client, server string, interval time.Duration, hook string, concurrency int) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	// Ctrl-C cancels in-flight checks and ends the watch cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	opts := app.WatchOptions{Client: client, Server: server, Interval: interval, Hook: hook, Concurrency: concurrency}
+	return a.HealthWatch(ctx, opts, os.Stdout)
+}
+func runHealthHistory(server, since string, jsonOut bool) error {
+	lookback, err := history.ParseSince(since)
+	if err != nil {
+		return err
+	}
 	a, err := app.LoadApp()
 	if err != nil {
 		return err
 	}
-	output, err := a.Health(client, server, fix, false)
+	output, err := a.HealthHistory(server, lookback, jsonOut)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+func runLogs(query string, lines int, follow, jsonOut bool) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.Logs(query, lines, jsonOut)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	if !follow {
+		return nil
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return a.FollowLogs(ctx, query, os.Stdout)
+}
+func runInspect(query string, cached bool, timeout time.Duration, jsonOut bool) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.Inspect(query, cached, timeout, jsonOut)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+func runPin(query string, yes bool, timeout time.Duration) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.PinTools(query, yes, timeout)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+func runUnpin(query string) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.Unpin(query)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+func runScan(server, format string, cached bool, timeout time.Duration) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.Scan(server, format, cached, timeout)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+func runDoctor(client, server string, jsonOut bool) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.Doctor(client, server, jsonOut)
 	if err != nil {
 		return err
 	}
@@ -75,15 +257,36 @@ func runProfilesList(jsonOut bool) error {
 	return nil
 }
 
-func runProfilesCreate(name string, servers []string) error {
+func runProfilesShow(name string, jsonOut bool) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.ShowProfile(name, jsonOut)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+func runProfilesCreate(name string, servers, extends, exclude, tags []string, query string, clients, days []string, window string) error {
 	a, err := app.LoadApp()
 	if err != nil {
 		return err
 	}
-	if err := a.CreateProfile(name, servers); err != nil {
+	schedule, err := config.ParseSchedule(days, window)
+	if err != nil {
+		return err
+	}
+	if err := a.CreateProfile(name, config.Profile{Extends: extends, Include: servers, Exclude: exclude, Tags: tags, Query: query, Clients: clients, Schedule: schedule}); err != nil {
+		return err
+	}
+	resolved, err := a.Canon.ResolveProfile(name)
+	if err != nil {
 		return err
 	}
-	fmt.Print(style.Success(fmt.Sprintf("Profile %q created with %d servers", name, len(servers))) + "\n")
+	fmt.Print(style.Success(fmt.Sprintf("Profile %q created with %d servers", name, len(resolved.Members))) + "\n")
 	return nil
 }
 
@@ -111,13 +314,157 @@ func runProfilesDelete(name string) error {
 	return nil
 }
 
-func runProfilesApply(name string) error {
+func runProfilesRename(name, newName string) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	changed, err := a.RenameProfile(name, newName)
+	if err != nil {
+		return err
+	}
+	fmt.Print(style.Success(fmt.Sprintf("Profile %q renamed to %q", name, newName)) + "\n")
+	if len(changed) > 0 {
+		fmt.Print(style.Muted("  updated extends in: "+strings.Join(changed, ", ")) + "\n")
+	}
+	return nil
+}
+
+func runProfilesCopy(name, newName string) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	if err := a.CopyProfile(name, newName); err != nil {
+		return err
+	}
+	fmt.Print(style.Success(fmt.Sprintf("Profile %q copied to %q", name, newName)) + "\n")
+	return nil
+}
+
+func runProfilesAdd(name string, queries []string, yes bool) error {
 	a, err := app.LoadApp()
 	if err != nil {
 		return err
 	}
-	if err := a.Apply("", name, false); err != nil {
+	output, err := a.AddToProfile(name, queries, yes)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+func runProfilesRemove(name string, queries []string, yes bool) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.RemoveFromProfile(name, queries, yes)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+func runRename(query, newName string, yes bool) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	return a.RenameServer(query, newName, yes)
+}
+
+func runProfilesApply(name, mode string) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	if err := a.ApplyWithMode("", name, mode, false); err != nil {
+		return err
+	}
+	return nil
+}
+
+func runProfilesDiff(to, from string, jsonOut bool) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.DiffProfiles(to, from, jsonOut)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+func runProfilesUndo(yes bool) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	return a.UndoProfile(yes)
+}
+
+func runProfilesExport(name string) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.ExportProfile(name)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+func runProfilesImport(path, onConflict string, yes bool) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.ImportBundle(path, onConflict, yes)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+func runUse(dir string, auto bool) error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.Use(dir, auto)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+func runUnuse() error {
+	a, err := app.LoadApp()
+	if err != nil {
+		return err
+	}
+	output, err := a.Unuse()
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+func runHook(shell string) error {
+	script, err := app.Hook(shell)
+	if err != nil {
 		return err
 	}
+	fmt.Print(script)
 	return nil
 }
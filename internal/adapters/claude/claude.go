@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"runtime"
 	"time"
 
 	"mseep/internal/config"
@@ -33,6 +34,26 @@ func (Adapter) Path() (string, error) {
 	return filepath.Join(h, "Library", "Application Support", "Claude", "claude_desktop_config.json"), nil
 }
 
+// LogDir is where Claude Desktop writes its MCP logs
+// macOS: ~/Library/Logs/Claude; Windows: %APPDATA%\Claude\logs
+func (Adapter) LogDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "Claude", "logs"), nil
+	}
+	h, err := os.UserHomeDir()
+	if err != nil { return "", err }
+	if runtime.GOOS == "darwin" {
+		return filepath.Join(h, "Library", "Logs", "Claude"), nil
+	}
+	return filepath.Join(h, ".config", "Claude", "logs"), nil
+}
+
+// LogPath is the per-server log Claude Desktop writes with the server's stderr
+func (a Adapter) LogPath(server string) (string, error) {
+	dir, err := a.LogDir(); if err != nil { return "", err }
+	return filepath.Join(dir, "mcp-server-"+server+".log"), nil
+}
+
 func (a Adapter) Detect() (bool, error) {
 	p, err := a.Path(); if err != nil { return false, err }
 	_, err = os.Stat(p)
@@ -80,7 +101,7 @@ func (a Adapter) Apply(canon *config.Canonical) (string, error) {
 	// build desired set from canonical enabled servers
 	enabled := map[string]config.Server{}
 	for _, s := range canon.Servers {
-		if s.Enabled {
+		if s.EnabledIn("claude") {
 			enabled[s.Name] = s
 		}
 	}
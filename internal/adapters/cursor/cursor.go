@@ -183,7 +183,7 @@ func (a Adapter) Apply(canon *config.Canonical) (string, error) {
 	// Build desired set from canonical enabled servers
 	enabled := map[string]config.Server{}
 	for _, s := range canon.Servers {
-		if s.Enabled {
+		if s.EnabledIn("cursor") {
 			enabled[s.Name] = s
 		}
 	}
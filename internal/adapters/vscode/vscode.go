@@ -186,11 +186,11 @@ func (a Adapter) Restore(path string) error {
 	return os.WriteFile(p, b, 0o644)
 }
 
-// Apply merges canonical servers into VS Code config, preserving unmanaged entries and other settings.
-func (a Adapter) Apply(canon *config.Canonical) (string, error) {
+// plan returns the config before and after merging canon
+func (a Adapter) plan(canon *config.Canonical) ([]byte, []byte, error) {
 	cc, err := a.Load()
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 	
 	// Create the full config map for before/after comparison
@@ -206,7 +206,7 @@ func (a Adapter) Apply(canon *config.Canonical) (string, error) {
 	// Build desired set from canonical enabled servers
 	enabled := map[string]config.Server{}
 	for _, s := range canon.Servers {
-		if s.Enabled {
+		if s.EnabledIn("vscode") {
 			enabled[s.Name] = s
 		}
 	}
@@ -237,7 +237,24 @@ func (a Adapter) Apply(canon *config.Canonical) (string, error) {
 		afterMap["mcp.servers"] = newServers
 	}
 	after, _ := json.MarshalIndent(afterMap, "", "  ")
-	
+	return before, after, nil
+}
+
+// Diff returns the changes Apply would make without writing anything
+func (a Adapter) Diff(canon *config.Canonical) (string, error) {
+	before, after, err := a.plan(canon)
+	if err != nil {
+		return "", err
+	}
+	return diff.GenerateColorDiff(string(before), string(after)), nil
+}
+
+// Apply merges canonical servers into VS Code config, preserving unmanaged entries and other settings.
+func (a Adapter) Apply(canon *config.Canonical) (string, error) {
+	before, after, err := a.plan(canon)
+	if err != nil {
+		return "", err
+	}
 	diffStr := diff.GenerateColorDiff(string(before), string(after))
 
 	// Write the complete settings.json back
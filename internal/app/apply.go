@@ -3,6 +3,7 @@ package app
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -17,16 +18,46 @@ import (
 	"mseep/internal/style"
 )
 
+// Profile apply modes: exclusive enables exactly the profile's servers,
+// additive enables them and leaves other servers as they are
+const (
+	ModeExclusive = "exclusive"
+	ModeAdditive  = "additive"
+)
+
+// errDeclined is returned by the per-client applies when the user answers
+// no at the confirmation prompt
+var errDeclined = errors.New("changes not applied")
+
 // Apply applies the canonical configuration to the specified client
 func (a *App) Apply(client, profile string, autoApprove bool) error {
-	// Apply profile if specified
+	return a.ApplyWithMode(client, profile, ModeExclusive, autoApprove)
+}
+
+// ApplyWithMode applies profile, if any, in the given mode and then the
+// canonical configuration to the specified client
+func (a *App) ApplyWithMode(client, profile, mode string, autoApprove bool) error {
+	// Apply profile if specified. The new enablement is only held in memory
+	// until a client accepts it, so declining every prompt leaves the
+	// canonical config and the undo stash untouched.
+	var prev map[string]EnabledState
 	if profile != "" {
-		if err := a.applyProfile(profile); err != nil {
+		prev = a.enabledStates()
+		if err := a.applyProfile(profile, mode); err != nil {
 			return fmt.Errorf("failed to apply profile %q: %w", profile, err)
 		}
 	}
 
 	// Determine which clients to apply to
+	var targets map[string]bool
+	if profile != "" && client == "" {
+		for _, c := range a.Canon.Profiles[profile].Clients {
+			if targets == nil {
+				targets = map[string]bool{}
+			}
+			targets[c] = true
+		}
+	}
 	clients := []string{}
 	if client == "" || client == "all" {
 		// Apply to all detected clients
@@ -39,6 +70,9 @@ func (a *App) Apply(client, profile string, autoApprove bool) error {
 		}
 		
 		for name, adapter := range adapters {
+			if targets != nil && !targets[name] {
+				continue
+			}
 			if detectClient(adapter) {
 				clients = append(clients, name)
 			}
@@ -48,10 +82,12 @@ func (a *App) Apply(client, profile string, autoApprove bool) error {
 	}
 
 	if len(clients) == 0 {
+		a.restoreEnabled(prev)
 		return fmt.Errorf("no clients detected or specified")
 	}
 
 	// Apply to each client
+	applied, declined := 0, 0
 	for i, c := range clients {
 		if len(clients) > 1 {
 			fmt.Print(style.ProgressStep(i+1, len(clients), fmt.Sprintf("Applying configuration to %s", c)) + "\n")
@@ -59,67 +95,93 @@ func (a *App) Apply(client, profile string, autoApprove bool) error {
 			fmt.Print(style.Header(fmt.Sprintf("Applying configuration to %s", c)) + "\n")
 		}
 		
+		var err error
 		switch c {
 		case "claude":
-			if err := a.applyToClaude(autoApprove); err != nil {
-				return fmt.Errorf("failed to apply to claude: %w", err)
-			}
+			err = a.applyToClaude(autoApprove)
 		case "cursor":
-			if err := a.applytoCursor(autoApprove); err != nil {
-				return fmt.Errorf("failed to apply to cursor: %w", err)
-			}
+			err = a.applytoCursor(autoApprove)
 		case "vscode":
-			if err := a.applyToVSCode(autoApprove); err != nil {
-				return fmt.Errorf("failed to apply to vscode: %w", err)
-			}
+			err = a.applyToVSCode(autoApprove)
 		case "cline":
-			if err := a.applyToCline(autoApprove); err != nil {
-				return fmt.Errorf("failed to apply to cline: %w", err)
-			}
+			err = a.applyToCline(autoApprove)
 		case "warp":
-			if err := a.applyToWarp(autoApprove); err != nil {
-				return fmt.Errorf("failed to apply to warp: %w", err)
-			}
+			err = a.applyToWarp(autoApprove)
 		default:
+			a.restoreEnabled(prev)
 			return fmt.Errorf("unknown client: %s", c)
 		}
+		if errors.Is(err, errDeclined) {
+			declined++
+			continue
+		}
+		if err != nil {
+			// Keep the profile only if an earlier client already has it
+			if profile != "" && applied > 0 {
+				if serr := a.saveProfile(profile, mode, prev); serr != nil {
+					return serr
+				}
+			} else {
+				a.restoreEnabled(prev)
+			}
+			return fmt.Errorf("failed to apply to %s: %w", c, err)
+		}
+		applied++
 	}
 
-	return nil
+	if profile == "" {
+		return nil
+	}
+	if declined > 0 && applied == 0 {
+		a.restoreEnabled(prev)
+		fmt.Print(style.Warning(fmt.Sprintf("Profile %q not applied", profile)) + "\n")
+		return nil
+	}
+	return a.saveProfile(profile, mode, prev)
 }
 
-func (a *App) applyProfile(profileName string) error {
-	profile, exists := a.Canon.Profiles[profileName]
-	if !exists {
-		return fmt.Errorf("profile %q not found", profileName)
+func (a *App) applyProfile(profileName, mode string) error {
+	if mode != ModeExclusive && mode != ModeAdditive {
+		return fmt.Errorf("unknown apply mode %q (want %s or %s)", mode, ModeExclusive, ModeAdditive)
 	}
-
-	// Reset all servers to disabled
-	for i := range a.Canon.Servers {
-		a.Canon.Servers[i].Enabled = false
+	resolved, err := a.Canon.ResolveProfile(profileName)
+	if err != nil {
+		return err
 	}
 
-	// Enable servers in the profile
-	for _, serverName := range profile {
-		found := false
-		for i := range a.Canon.Servers {
-			if a.Canon.Servers[i].Name == serverName {
-				a.Canon.Servers[i].Enabled = true
-				found = true
-				break
-			}
+	// Enable the servers the profile resolves to, in every client or only
+	// in the clients the profile targets. Exclusive mode disables the rest.
+	clients := a.Canon.Profiles[profileName].Clients
+	for i := range a.Canon.Servers {
+		srv := &a.Canon.Servers[i]
+		on := resolved.Has(srv.Name)
+		if !on && mode == ModeAdditive {
+			continue
 		}
-		if !found {
-			fmt.Printf("Warning: Server %q in profile %q not found in canonical config\n", serverName, profileName)
+		if len(clients) == 0 {
+			srv.SetEnabled("", on)
+		}
+		for _, c := range clients {
+			srv.SetEnabled(c, on)
 		}
 	}
+	for _, serverName := range resolved.Missing {
+		fmt.Printf("Warning: Server %q in profile %q not found in canonical config\n", serverName, profileName)
+	}
+	return nil
+}
 
-	// Save the updated canonical config
+// saveProfile persists an applied profile and stashes prev, the enabled set
+// from before it, so 'mseep profiles undo' can restore it
+func (a *App) saveProfile(profileName, mode string, prev map[string]EnabledState) error {
+	if err := stashEnabled(profileName, mode, prev); err != nil {
+		return fmt.Errorf("failed to stash enabled servers: %w", err)
+	}
 	if err := config.Save("", a.Canon); err != nil {
 		return fmt.Errorf("failed to save canonical config: %w", err)
 	}
 
-	fmt.Print(style.Success(fmt.Sprintf("Applied profile %q", profileName)) + "\n")
+	fmt.Print(style.Success(fmt.Sprintf("Applied profile %q (%s)", profileName, mode)) + "\n")
 	return nil
 }
 
@@ -159,7 +221,7 @@ func (a *App) applyToClaude(autoApprove bool) error {
 
 	// Then add enabled servers from canonical
 	for _, srv := range a.Canon.Servers {
-		if srv.Enabled {
+		if srv.EnabledIn("claude") {
 			newConfig.MCPServers[srv.Name] = claude.ClaudeServer{
 				Command: srv.Command,
 				Args:    srv.Args,
@@ -194,7 +256,7 @@ func (a *App) applyToClaude(autoApprove bool) error {
 		response = strings.ToLower(strings.TrimSpace(response))
 		if response != "y" && response != "yes" {
 			fmt.Print(style.Warning("Changes not applied") + "\n")
-			return nil
+			return errDeclined
 		}
 	}
 
@@ -263,7 +325,7 @@ func (a *App) applytoCursor(autoApprove bool) error {
 
 	// Then add enabled servers from canonical
 	for _, srv := range a.Canon.Servers {
-		if srv.Enabled {
+		if srv.EnabledIn("cursor") {
 			newConfig.MCPServers[srv.Name] = cursor.CursorServer{
 				Command: srv.Command,
 				Args:    srv.Args,
@@ -314,7 +376,7 @@ func (a *App) applytoCursor(autoApprove bool) error {
 		response = strings.ToLower(strings.TrimSpace(response))
 		if response != "y" && response != "yes" {
 			fmt.Print(style.Warning("Changes not applied") + "\n")
-			return nil
+			return errDeclined
 		}
 	}
 
@@ -357,8 +419,8 @@ func (a *App) applyToWarp(autoApprove bool) error {
 func (a *App) applyToGenericClient(adapter interface {
 	Name() string
 	Detect() (bool, error)
+	Diff(*config.Canonical) (string, error)
 	Apply(*config.Canonical) (string, error)
-	Backup() (string, error)
 	Path() (string, error)
 }, clientName string, autoApprove bool) error {
 	
@@ -367,8 +429,8 @@ func (a *App) applyToGenericClient(adapter interface {
 		return fmt.Errorf("%s not detected", clientName)
 	}
 
-	// Generate diff preview
-	diffStr, err := adapter.Apply(a.Canon)
+	// Generate diff preview; nothing is written until it is approved
+	diffStr, err := adapter.Diff(a.Canon)
 	if err != nil {
 		return fmt.Errorf("failed to apply to %s: %w", clientName, err)
 	}
@@ -394,11 +456,14 @@ func (a *App) applyToGenericClient(adapter interface {
 		response = strings.ToLower(strings.TrimSpace(response))
 		if response != "y" && response != "yes" {
 			fmt.Print(style.Warning("Changes not applied") + "\n")
-			return nil
+			return errDeclined
 		}
 	}
 
-	// Create backup (already done in Apply, but get path for display)
+	// Apply backs up the config before writing it
+	if _, err := adapter.Apply(a.Canon); err != nil {
+		return fmt.Errorf("failed to apply to %s: %w", clientName, err)
+	}
 	configPath, err := adapter.Path()
 	if err != nil {
 		return fmt.Errorf("failed to get config path: %w", err)
@@ -0,0 +1,174 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"mseep/internal/bundle"
+	"mseep/internal/config"
+	"mseep/internal/style"
+)
+
+// ExportProfile returns a bundle of profile name as indented JSON, with
+// secret values replaced by placeholders
+func (a *App) ExportProfile(name string) (string, error) {
+	b, err := bundle.Export(a.Canon, name)
+	if err != nil {
+		return "", err
+	}
+	// Keep the placeholder readable rather than \u003csecret\u003e
+	var output strings.Builder
+	enc := json.NewEncoder(&output)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(b); err != nil {
+		return "", fmt.Errorf("error formatting json: %w", err)
+	}
+	return output.String(), nil
+}
+
+// ImportBundle merges the bundle at path into the canonical config.
+// onConflict is prompt, rename, replace or skip; with assumeYes nothing is
+// asked, conflicts left to prompt are renamed and missing secrets are left
+// unset.
+func (a *App) ImportBundle(path, onConflict string, assumeYes bool) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", err
+	}
+	b, err := bundle.Parse(data)
+	if err != nil {
+		return "", err
+	}
+
+	r := &promptResolver{reader: bufio.NewReader(os.Stdin), assumeYes: assumeYes}
+	switch onConflict {
+	case "prompt":
+		if assumeYes {
+			r.action = bundle.ActionRename
+		}
+	case string(bundle.ActionRename), string(bundle.ActionReplace), string(bundle.ActionSkip):
+		r.action = bundle.Action(onConflict)
+	default:
+		return "", fmt.Errorf("unknown conflict policy %q (want prompt, rename, replace or skip)", onConflict)
+	}
+
+	res, err := bundle.Merge(a.Canon, b, r)
+	if err != nil {
+		return "", err
+	}
+	if err := config.Save("", a.Canon); err != nil {
+		return "", fmt.Errorf("failed to save configuration: %w", err)
+	}
+	return formatImport(res), nil
+}
+
+func formatImport(res *bundle.Result) string {
+	var output strings.Builder
+	output.WriteString(style.Success(fmt.Sprintf("Imported profile %q", res.Profile)) + "\n")
+	for _, name := range res.Added {
+		output.WriteString(style.ListItem("added "+name) + "\n")
+	}
+	renamed := make([]string, 0, len(res.Renamed))
+	for from := range res.Renamed {
+		renamed = append(renamed, from)
+	}
+	sort.Strings(renamed)
+	for _, from := range renamed {
+		output.WriteString(style.Muted(fmt.Sprintf("  %s imported as %s (name taken)", from, res.Renamed[from])) + "\n")
+	}
+	for _, name := range res.Replaced {
+		output.WriteString(style.ListItem("replaced "+name+" (now disabled)") + "\n")
+	}
+	for _, name := range res.Kept {
+		output.WriteString(style.Muted("  "+name+" already defined, kept") + "\n")
+	}
+	for _, name := range res.Skipped {
+		output.WriteString(style.Muted("  "+name+" skipped, existing definition kept") + "\n")
+	}
+	if len(res.Profiles) > 1 {
+		output.WriteString(style.Muted("  profiles: "+strings.Join(res.Profiles, ", ")) + "\n")
+	}
+	for _, s := range res.Unset {
+		output.WriteString(style.Warning(fmt.Sprintf("%s not set; add it to the canonical config before enabling %s", s, s.Server)) + "\n")
+	}
+	if len(res.Added) > 0 || len(res.Replaced) > 0 {
+		output.WriteString("\n" + style.Muted(fmt.Sprintf("Imported servers are disabled. Run 'mseep profiles apply %s' to enable them.", res.Profile)) + "\n")
+	}
+	return output.String()
+}
+
+// promptResolver settles import conflicts with a fixed action or by
+// asking on stdin
+type promptResolver struct {
+	reader    *bufio.Reader
+	action    bundle.Action
+	assumeYes bool
+}
+
+func (r *promptResolver) ServerConflict(existing, incoming config.Server, suggested string) (bundle.Action, string, error) {
+	if r.action != "" {
+		return r.action, suggested, nil
+	}
+	fmt.Print(style.Warning(fmt.Sprintf("Server %q already exists with a different definition", existing.Name)) + "\n")
+	fmt.Printf("  existing: %s\n  incoming: %s\n", commandLine(existing), commandLine(incoming))
+	return r.ask(suggested)
+}
+
+func (r *promptResolver) ProfileConflict(name, suggested string) (bundle.Action, string, error) {
+	if r.action != "" {
+		return r.action, suggested, nil
+	}
+	fmt.Print(style.Warning(fmt.Sprintf("Profile %q already exists", name)) + "\n")
+	return r.ask(suggested)
+}
+
+func (r *promptResolver) ask(suggested string) (bundle.Action, string, error) {
+	for {
+		fmt.Printf("[r]ename to %s / [o]verwrite / [s]kip: ", suggested)
+		response, err := r.reader.ReadString('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || response == "") {
+			return "", "", fmt.Errorf("failed to read response: %w", err)
+		}
+		switch strings.ToLower(strings.TrimSpace(response)) {
+		case "r", "rename":
+			return bundle.ActionRename, suggested, nil
+		case "o", "overwrite":
+			return bundle.ActionReplace, "", nil
+		case "s", "skip":
+			return bundle.ActionSkip, "", nil
+		}
+	}
+}
+
+func (r *promptResolver) Secret(s bundle.Secret) (string, error) {
+	if r.assumeYes {
+		return "", nil
+	}
+	what := s.Field
+	if s.Key != "" {
+		what = s.Key
+	}
+	fmt.Printf("Enter %s for %s (env:NAME or file:PATH references work, empty to skip): ", what, s.Server)
+	response, err := r.reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	return strings.TrimSpace(response), nil
+}
+
+func commandLine(s config.Server) string {
+	return strings.TrimSpace(s.Command + " " + strings.Join(s.Args, " "))
+}
@@ -0,0 +1,226 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"mseep/internal/adapters/claude"
+	"mseep/internal/adapters/cline"
+	"mseep/internal/adapters/cursor"
+	"mseep/internal/adapters/vscode"
+	"mseep/internal/adapters/warp"
+	"mseep/internal/config"
+	"mseep/internal/health"
+	"mseep/internal/launchenv"
+	"mseep/internal/preflight"
+	"mseep/internal/style"
+)
+
+// DoctorReport holds preflight results per client launch environment
+type DoctorReport struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Clients   []DoctorClient `json:"clients"`
+}
+
+// DoctorClient is the preflight of every server in one client's environment
+type DoctorClient struct {
+	Client  string             `json:"client"`
+	Name    string             `json:"name"`
+	Minimal bool               `json:"minimalPath"`
+	Servers []preflight.Report `json:"servers"`
+}
+
+// Doctor checks that each server's command, runtime and package resolve
+// the way each client would launch them. With client empty, every
+// detected client is checked, falling back to the current shell.
+func (a *App) Doctor(client, serverFilter string, jsonOutput bool) (string, error) {
+	var servers []config.Server
+	for _, srv := range a.Canon.Servers {
+		if serverFilter != "" {
+			if matchesFilter(srv, serverFilter) {
+				servers = append(servers, srv)
+			}
+		} else if srv.EnabledAnywhere() {
+			servers = append(servers, srv)
+		}
+	}
+	if len(servers) == 0 {
+		return "", fmt.Errorf("no servers found matching criteria")
+	}
+
+	clients := []string{client}
+	if client == "" {
+		clients = detectedClients()
+		if len(clients) == 0 {
+			clients = []string{"shell"}
+		}
+	}
+
+	ctx := context.Background()
+	report := DoctorReport{Timestamp: time.Now()}
+	for _, c := range clients {
+		env, err := launchenv.For(c)
+		if err != nil {
+			return "", err
+		}
+		runner := preflight.New(env)
+		dc := DoctorClient{Client: env.Client, Name: env.Name, Minimal: env.Minimal}
+		for _, srv := range servers {
+			dc.Servers = append(dc.Servers, runner.Run(ctx, srv))
+		}
+		report.Clients = append(report.Clients, dc)
+	}
+
+	if jsonOutput {
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("error formatting json: %w", err)
+		}
+		return string(output), nil
+	}
+
+	return formatDoctorReport(report), nil
+}
+
+// detectedClients returns the installed clients in display order
+func detectedClients() []string {
+	adapters := map[string]interface{ Detect() (bool, error) }{
+		"claude": claude.Adapter{},
+		"cursor": cursor.Adapter{},
+		"vscode": vscode.Adapter{},
+		"cline":  cline.Adapter{},
+		"warp":   warp.Adapter{},
+	}
+	var out []string
+	for _, name := range launchenv.Clients {
+		if detectClient(adapters[name]) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// newHealthManager returns a health manager that launches servers the way
+// client would (empty means the current shell), running at most
+// concurrency checks at once (zero uses the default)
+func newHealthManager(client string, concurrency int) (*health.Manager, error) {
+	env, err := launchenv.For(client)
+	if err != nil {
+		return nil, err
+	}
+	manager := health.NewManagerFor(env)
+	manager.Concurrency = concurrency
+	return manager, nil
+}
+
+// runChecks preflights stdio servers in the manager's launch environment
+// and health checks the rest. Servers that cannot start are reported
+// unhealthy without launching them. Results keep the order of servers.
+func (a *App) runChecks(ctx context.Context, manager *health.Manager, servers []config.Server) ([]health.CheckResult, []preflight.Report) {
+	runner := preflight.New(manager.Env)
+
+	results := make([]health.CheckResult, len(servers))
+	var ready []config.Server
+	var readyIdx []int
+	var problems []preflight.Report
+	for i, srv := range servers {
+		checkType := "stdio"
+		if srv.Health != nil && srv.Health.Type != "" {
+			checkType = srv.Health.Type
+		}
+		if checkType == "stdio" && preflight.Applies(srv) {
+			rep := runner.Run(ctx, srv)
+			if len(rep.Problems()) > 0 {
+				problems = append(problems, rep)
+			}
+			if rep.Failed() {
+				results[i] = health.CheckResult{
+					ServerName: srv.Name,
+					Type:       checkType,
+					Status:     health.StatusUnhealthy,
+					Message:    "preflight: " + rep.Problems()[0].Detail,
+					Timestamp:  time.Now(),
+				}
+				continue
+			}
+		}
+		ready = append(ready, srv)
+		readyIdx = append(readyIdx, i)
+	}
+
+	for j, r := range manager.CheckServers(ctx, ready) {
+		results[readyIdx[j]] = r
+	}
+	return results, problems
+}
+
+// formatPreflightProblems lists non-passing checks with their fixes
+func formatPreflightProblems(reports []preflight.Report) string {
+	var output strings.Builder
+	for _, rep := range reports {
+		for _, c := range rep.Problems() {
+			line := fmt.Sprintf("%s: %s", rep.Server, c.Detail)
+			if c.Status == preflight.StatusFail {
+				output.WriteString(style.Error(line) + "\n")
+			} else {
+				output.WriteString(style.Warning(line) + "\n")
+			}
+			if c.Fix != "" {
+				output.WriteString(style.Muted("  fix: "+c.Fix) + "\n")
+			}
+		}
+	}
+	return output.String()
+}
+
+func formatDoctorReport(report DoctorReport) string {
+	var output strings.Builder
+
+	output.WriteString(style.Title("Doctor"))
+	output.WriteString("\n")
+
+	ready, warned, failed := 0, 0, 0
+	for _, dc := range report.Clients {
+		title := dc.Name
+		if dc.Minimal {
+			title += " (minimal PATH, shell profile not loaded)"
+		}
+		output.WriteString(style.Header(title) + "\n")
+
+		for _, rep := range dc.Servers {
+			switch {
+			case len(rep.Checks) == 0:
+				output.WriteString(style.Muted(fmt.Sprintf("- %s: remote, nothing to launch", rep.Server)) + "\n")
+				continue
+			case rep.Failed():
+				failed++
+				output.WriteString(style.Error(rep.Server) + "\n")
+			case len(rep.Problems()) > 0:
+				warned++
+				output.WriteString(style.Warning(rep.Server) + "\n")
+			default:
+				ready++
+				output.WriteString(style.Success(rep.Server) + "\n")
+			}
+			for _, c := range rep.Checks {
+				icon := "✓"
+				switch c.Status {
+				case preflight.StatusWarn:
+					icon = "⚠"
+				case preflight.StatusFail:
+					icon = "✗"
+				}
+				output.WriteString(fmt.Sprintf("    %s %s\n", icon, c.Detail))
+				if c.Fix != "" {
+					output.WriteString(style.Muted("      fix: "+c.Fix) + "\n")
+				}
+			}
+		}
+	}
+
+	output.WriteString("\n" + style.Muted(fmt.Sprintf("%d ready, %d with warnings, %d failing", ready, warned, failed)) + "\n")
+	return output.String()
+}
@@ -10,14 +10,23 @@ import (
 	"mseep/internal/adapters/claude"
 	"mseep/internal/config"
 	"mseep/internal/health"
+	"mseep/internal/history"
+	"mseep/internal/mcp"
+	"mseep/internal/pin"
+	"mseep/internal/policy"
+	"mseep/internal/preflight"
 	"mseep/internal/style"
 )
 
 // HealthReport represents the results of health checks
 type HealthReport struct {
-	Timestamp time.Time             `json:"timestamp"`
-	Results   []health.CheckResult  `json:"results"`
-	Summary   HealthSummary         `json:"summary"`
+	Timestamp  time.Time            `json:"timestamp"`
+	Results    []health.CheckResult `json:"results"`
+	Summary    HealthSummary        `json:"summary"`
+	Drift      []*pin.Drift         `json:"toolDrift,omitempty"`
+	Unverified []PinFailure         `json:"unverifiedPins,omitempty"`
+	Policy     []policy.Decision    `json:"policyActions,omitempty"`
+	Preflight  []preflight.Report   `json:"preflight,omitempty"`
 }
 
 // HealthSummary provides aggregate health information
@@ -27,48 +36,134 @@ type HealthSummary struct {
 	Unhealthy int `json:"unhealthy"`
 	Timeout   int `json:"timeout"`
 	Error     int `json:"error"`
+	Protocol  int `json:"protocol_error"`
+	// Drifted counts pinned servers whose tools changed since approval
+	Drifted int `json:"tool_drift,omitempty"`
+	// Unverified counts pinned servers whose tools could not be fetched
+	Unverified int `json:"unverified_pins,omitempty"`
 }
 
-// Health performs health checks on servers
-func (a *App) Health(client, serverFilter string, fix bool, jsonOutput bool) (string, error) {
+// Exit codes for 'mseep health' so it can gate CI pipelines. 1 is left for
+// mseep's own errors.
+const (
+	ExitHealthy    = 0
+	ExitUnhealthy  = 2 // at least one server unhealthy or speaking broken MCP
+	ExitTimeout    = 3 // no failures, but at least one check timed out
+	ExitCheckErr   = 4 // only misconfigured health checks
+	ExitUnverified = 5 // all healthy, but some tool pins could not be verified
+	ExitDrift      = 6 // no failures, but some pinned tools changed since approval
+)
+
+// ExitCode maps the summary counts to a process exit code. Failures take
+// precedence over tool drift, drift over timeouts, timeouts over check
+// errors, and check errors over unverifiable pins.
+func (s HealthSummary) ExitCode() int {
+	switch {
+	case s.Unhealthy > 0 || s.Protocol > 0:
+		return ExitUnhealthy
+	case s.Drifted > 0:
+		return ExitDrift
+	case s.Timeout > 0:
+		return ExitTimeout
+	case s.Error > 0:
+		return ExitCheckErr
+	case s.Unverified > 0:
+		return ExitUnverified
+	}
+	return ExitHealthy
+}
+
+// Health performs health checks on servers. format is one of table, json,
+// junit, sarif or prom; the returned summary lets callers derive an exit code.
+// concurrency caps simultaneous checks; zero uses the default.
+func (a *App) Health(client, serverFilter string, fix bool, format string, concurrency int) (string, HealthSummary, error) {
+	switch format {
+	case "", "table", "json", "junit", "sarif", "prom":
+	default:
+		return "", HealthSummary{}, fmt.Errorf("unknown format %q (want table, json, junit, sarif or prom)", format)
+	}
+	
 	ctx := context.Background()
-	manager := health.NewManager()
+	manager, err := newHealthManager(client, concurrency)
+	if err != nil {
+		return "", HealthSummary{}, err
+	}
 	
 	// Filter servers based on criteria
 	servers := a.getServersForHealthCheck(client, serverFilter)
 	if len(servers) == 0 {
-		return "", fmt.Errorf("no servers found matching criteria")
+		return "", HealthSummary{}, fmt.Errorf("no servers found matching criteria")
 	}
 	
-	// Perform health checks
-	results := manager.CheckServers(ctx, servers)
+	// Preflight launch commands as the client would see them, then check
+	results, preflights := a.runChecks(ctx, manager, servers)
 	
 	// Create summary
 	summary := createHealthSummary(results)
 	
+	// Record results so flaky servers show up in 'mseep health history'
+	store, err := history.Open()
+	if err != nil {
+		return "", summary, err
+	}
+	if err := store.Append(results); err != nil {
+		return "", summary, fmt.Errorf("failed to record health history: %w", err)
+	}
+	
+	// Flag pinned servers whose tool definitions changed since approval
+	drifts, unverified, err := a.verifyPins(ctx, manager, results)
+	if err != nil {
+		return "", summary, fmt.Errorf("failed to verify tool pins: %w", err)
+	}
+	summary.Drifted = len(drifts)
+	summary.Unverified = len(unverified)
+	
 	report := HealthReport{
-		Timestamp: time.Now(),
-		Results:   results,
-		Summary:   summary,
+		Timestamp:  time.Now(),
+		Results:    results,
+		Summary:    summary,
+		Drift:      drifts,
+		Unverified: unverified,
+		Preflight:  preflights,
 	}
 	
-	// Handle fix flag - disable failing servers
-	if fix {
-		if err := a.handleHealthFixes(results); err != nil {
-			return "", fmt.Errorf("failed to apply health fixes: %w", err)
-		}
+	// Enforce failure policies; the fix flag applies them to every server
+	// and also disables drifted servers
+	decisions, err := a.handleHealthFixes(store, results, drifts, fix)
+	if err != nil {
+		return "", summary, fmt.Errorf("failed to apply health fixes: %w", err)
 	}
+	report.Policy = decisions
 	
 	// Format output
-	if jsonOutput {
+	exportDrifts := make([]health.Drift, 0, len(drifts))
+	for _, d := range drifts {
+		exportDrifts = append(exportDrifts, health.Drift{Server: d.Server, Message: d.Summary()})
+	}
+	switch format {
+	case "json":
 		output, err := json.MarshalIndent(report, "", "  ")
 		if err != nil {
-			return "", fmt.Errorf("error formatting json: %w", err)
+			return "", summary, fmt.Errorf("error formatting json: %w", err)
 		}
-		return string(output), nil
+		return string(output), summary, nil
+	case "junit":
+		output, err := health.JUnit(results, exportDrifts, report.Timestamp)
+		if err != nil {
+			return "", summary, fmt.Errorf("error formatting junit: %w", err)
+		}
+		return string(output), summary, nil
+	case "sarif":
+		output, err := health.SARIF(results, exportDrifts, mcp.ClientVersion)
+		if err != nil {
+			return "", summary, fmt.Errorf("error formatting sarif: %w", err)
+		}
+		return string(output), summary, nil
+	case "prom":
+		return string(health.Prometheus(results, exportDrifts)), summary, nil
 	}
 	
-	return a.formatHealthReport(report), nil
+	return a.formatHealthReport(report), summary, nil
 }
 
 func (a *App) getServersForHealthCheck(client, serverFilter string) []config.Server {
@@ -96,14 +191,19 @@ func (a *App) getServersForHealthCheck(client, serverFilter string) []config.Ser
 					}
 				}
 			}
-		case "cursor", "cline":
-			// TODO: Implement when adapters are available
-			return servers
+		default:
+			// Other clients receive the enabled canonical servers on apply
+			for _, srv := range a.Canon.Servers {
+				if srv.EnabledIn(client) && (serverFilter == "" || matchesFilter(srv, serverFilter)) {
+					servers = append(servers, srv)
+				}
+			}
 		}
 	} else {
-		// Check all enabled servers in canonical config
+		// Check all enabled servers in canonical config, plus servers the
+		// policy disabled so they can be re-enabled once healthy
 		for _, srv := range a.Canon.Servers {
-			if srv.Enabled && (serverFilter == "" || matchesFilter(srv, serverFilter)) {
+			if (srv.EnabledAnywhere() || srv.AutoDisabled != nil) && (serverFilter == "" || matchesFilter(srv, serverFilter)) {
 				servers = append(servers, srv)
 			}
 		}
@@ -113,28 +213,7 @@ func (a *App) getServersForHealthCheck(client, serverFilter string) []config.Ser
 }
 
 func matchesFilter(server config.Server, filter string) bool {
-	filter = strings.ToLower(filter)
-	
-	// Check name
-	if strings.Contains(strings.ToLower(server.Name), filter) {
-		return true
-	}
-	
-	// Check aliases
-	for _, alias := range server.Aliases {
-		if strings.Contains(strings.ToLower(alias), filter) {
-			return true
-		}
-	}
-	
-	// Check tags
-	for _, tag := range server.Tags {
-		if strings.Contains(strings.ToLower(tag), filter) {
-			return true
-		}
-	}
-	
-	return false
+	return server.Matches(filter)
 }
 
 func createHealthSummary(results []health.CheckResult) HealthSummary {
@@ -150,44 +229,75 @@ func createHealthSummary(results []health.CheckResult) HealthSummary {
 			summary.Timeout++
 		case health.StatusError:
 			summary.Error++
+		case health.StatusProtocolError:
+			summary.Protocol++
 		}
 	}
 	
 	return summary
 }
 
-func (a *App) handleHealthFixes(results []health.CheckResult) error {
-	var serversToDisable []string
+// handleHealthFixes evaluates each checked server's policy against its
+// recorded history, disabling servers that crossed their failure threshold
+// and re-enabling policy-disabled servers whose cooldown has passed. With
+// fix set, servers without AutoDisable use the default policy, and servers
+// whose pinned tools changed are disabled outright.
+func (a *App) handleHealthFixes(store *history.Store, results []health.CheckResult, drifts []*pin.Drift, fix bool) ([]policy.Decision, error) {
+	entries, err := store.Query("", time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read health history: %w", err)
+	}
+	
+	now := time.Now()
+	var decisions []policy.Decision
 	
 	for _, result := range results {
-		if result.Status != health.StatusHealthy {
-			serversToDisable = append(serversToDisable, result.ServerName)
+		srv := a.Canon.FindByName(result.ServerName)
+		if srv == nil {
+			continue
 		}
+		
+		d := policy.Evaluate(*srv, entries, result, now, fix)
+		// The policy only sets and clears AutoDisabled, which turns the
+		// server off in every client, so the user's per-client choices
+		// come back unchanged when it is re-enabled
+		switch d.Action {
+		case policy.ActionDisable:
+			srv.AutoDisabled = &config.AutoDisabled{At: now, Reason: d.Reason}
+		case policy.ActionEnable:
+			srv.AutoDisabled = nil
+		default:
+			continue
+		}
+		decisions = append(decisions, d)
 	}
 	
-	if len(serversToDisable) == 0 {
-		return nil
-	}
-	
-	// Disable unhealthy servers in canonical config
-	for i := range a.Canon.Servers {
-		for _, name := range serversToDisable {
-			if a.Canon.Servers[i].Name == name {
-				a.Canon.Servers[i].Enabled = false
-				break
+	if fix {
+		// Changed tools need review, so these are not re-enabled by cooldown
+		for _, drift := range drifts {
+			srv := a.Canon.FindByName(drift.Server)
+			if srv == nil || !srv.EnabledAnywhere() {
+				continue
 			}
+			srv.SetEnabled("", false)
+			decisions = append(decisions, policy.Decision{
+				Server: drift.Server,
+				Action: policy.ActionDisable,
+				Reason: "tool definitions changed since approval",
+			})
 		}
 	}
 	
+	if len(decisions) == 0 {
+		return nil, nil
+	}
+	
 	// Save canonical config
 	if err := config.Save("", a.Canon); err != nil {
-		return err
+		return nil, err
 	}
 	
-	fmt.Print(style.Warning(fmt.Sprintf("Disabled %d unhealthy servers in canonical config", len(serversToDisable))) + "\n")
-	fmt.Print(style.Muted("Run 'mseep apply' to sync changes to clients") + "\n")
-	
-	return nil
+	return decisions, nil
 }
 
 func (a *App) formatHealthReport(report HealthReport) string {
@@ -214,6 +324,9 @@ func (a *App) formatHealthReport(report HealthReport) string {
 	if report.Summary.Error > 0 {
 		summaryParts = append(summaryParts, style.Error(fmt.Sprintf("%d error", report.Summary.Error)))
 	}
+	if report.Summary.Protocol > 0 {
+		summaryParts = append(summaryParts, style.Error(fmt.Sprintf("%d protocol error", report.Summary.Protocol)))
+	}
 	
 	output.WriteString(strings.Join(summaryParts, ", ") + "\n\n")
 	
@@ -225,17 +338,7 @@ func (a *App) formatHealthReport(report HealthReport) string {
 		headers := []string{"Server", "Type", "Status", "Duration", "Message"}
 		
 		for _, result := range report.Results {
-			statusStr := string(result.Status)
-			switch result.Status {
-			case health.StatusHealthy:
-				statusStr = "✓ " + statusStr
-			case health.StatusUnhealthy:
-				statusStr = "✗ " + statusStr
-			case health.StatusTimeout:
-				statusStr = "⏱ " + statusStr
-			case health.StatusError:
-				statusStr = "⚠ " + statusStr
-			}
+			statusStr := statusLabel(result.Status)
 			
 			duration := result.Duration.Round(time.Millisecond).String()
 			message := result.Message
@@ -256,12 +359,48 @@ func (a *App) formatHealthReport(report HealthReport) string {
 		output.WriteString(style.StatusTable(tableRows, headers))
 	}
 	
+	// Preflight problems
+	if len(report.Preflight) > 0 {
+		output.WriteString("\n" + style.Header("Preflight") + "\n")
+		output.WriteString(formatPreflightProblems(report.Preflight))
+	}
+	
+	// Tool drift
+	if len(report.Drift) > 0 {
+		output.WriteString("\n" + style.Header("Tool Changes Since Approval") + "\n")
+		for _, d := range report.Drift {
+			output.WriteString(formatDrift(d))
+		}
+		output.WriteString(style.Muted("• Review the changes, then run 'mseep pin <server>' to approve them") + "\n")
+	}
+	if len(report.Unverified) > 0 {
+		output.WriteString("\n" + style.Header("Unverified Tool Pins") + "\n")
+		for _, f := range report.Unverified {
+			output.WriteString(style.Warning(fmt.Sprintf("%s: could not fetch tools: %s", f.Server, f.Error)) + "\n")
+		}
+	}
+	
+	// Policy actions
+	if len(report.Policy) > 0 {
+		output.WriteString("\n" + style.Header("Policy Actions") + "\n")
+		for _, d := range report.Policy {
+			switch d.Action {
+			case policy.ActionDisable:
+				output.WriteString(style.Warning(fmt.Sprintf("Disabled %s: %s", d.Server, d.Reason)) + "\n")
+			case policy.ActionEnable:
+				output.WriteString(style.Success(fmt.Sprintf("Re-enabled %s: %s", d.Server, d.Reason)) + "\n")
+			}
+		}
+		output.WriteString(style.Muted("Run 'mseep apply' to sync changes to clients") + "\n")
+	}
+	
 	// Recommendations
-	if report.Summary.Unhealthy > 0 || report.Summary.Timeout > 0 || report.Summary.Error > 0 {
+	if report.Summary.Unhealthy > 0 || report.Summary.Timeout > 0 || report.Summary.Error > 0 || report.Summary.Protocol > 0 {
 		output.WriteString("\n" + style.Header("Recommendations"))
 		
 		if report.Summary.Unhealthy > 0 {
 			output.WriteString(style.Muted("• Check server configurations and dependencies") + "\n")
+			output.WriteString(style.Muted("• Run 'mseep logs <server>' to see captured output") + "\n")
 		}
 		if report.Summary.Timeout > 0 {
 			output.WriteString(style.Muted("• Consider increasing health check timeouts") + "\n")
@@ -269,12 +408,33 @@ func (a *App) formatHealthReport(report HealthReport) string {
 		if report.Summary.Error > 0 {
 			output.WriteString(style.Muted("• Review health check configurations") + "\n")
 		}
+		if report.Summary.Protocol > 0 {
+			output.WriteString(style.Muted("• Check that the server writes only MCP messages to stdout") + "\n")
+		}
 		
-		output.WriteString(style.Muted("• Use '--fix' flag to auto-disable failing servers") + "\n")
+		output.WriteString(style.Muted("• Use '--fix' flag to auto-disable servers that cross their failure threshold") + "\n")
 	}
 	
 	output.WriteString("\n" + style.Muted(fmt.Sprintf("Health check completed at %s", 
 		report.Timestamp.Format("2006-01-02 15:04:05"))) + "\n")
 	
 	return output.String()
-}
\ No newline at end of file
+}
+
+// statusLabel prefixes a check status with its table icon
+func statusLabel(status health.CheckStatus) string {
+	statusStr := string(status)
+	switch status {
+	case health.StatusHealthy:
+		statusStr = "✓ " + statusStr
+	case health.StatusUnhealthy:
+		statusStr = "✗ " + statusStr
+	case health.StatusTimeout:
+		statusStr = "⏱ " + statusStr
+	case health.StatusError:
+		statusStr = "⚠ " + statusStr
+	case health.StatusProtocolError:
+		statusStr = "✗ " + statusStr
+	}
+	return statusStr
+}
@@ -0,0 +1,20 @@
+package app
+
+import "testing"
+
+func TestHealthExitCode(t *testing.T) {
+	for _, tc := range []struct {
+		summary HealthSummary
+		want    int
+	}{
+		{HealthSummary{Healthy: 2}, ExitHealthy},
+		{HealthSummary{Unverified: 1}, ExitUnverified},
+		{HealthSummary{Drifted: 1, Unverified: 1, Timeout: 1}, ExitDrift},
+		{HealthSummary{Drifted: 1, Unhealthy: 1}, ExitUnhealthy},
+		{HealthSummary{Error: 1, Unverified: 1}, ExitCheckErr},
+	} {
+		if got := tc.summary.ExitCode(); got != tc.want {
+			t.Errorf("%+v: ExitCode() = %d, want %d", tc.summary, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"mseep/internal/health"
+	"mseep/internal/history"
+	"mseep/internal/style"
+)
+
+// HistoryReport summarizes recorded health checks over a lookback window
+type HistoryReport struct {
+	Since   time.Time             `json:"since"`
+	Servers []history.ServerStats `json:"servers"`
+}
+
+// HealthHistory summarizes recorded health checks per server since the
+// given lookback. serverFilter matches names, aliases and tags like
+// 'mseep health --server'.
+func (a *App) HealthHistory(serverFilter string, since time.Duration, jsonOutput bool) (string, error) {
+	store, err := history.Open()
+	if err != nil {
+		return "", err
+	}
+
+	report := HistoryReport{Since: time.Now().Add(-since)}
+	entries, err := store.Query("", report.Since)
+	if err != nil {
+		return "", fmt.Errorf("failed to read health history: %w", err)
+	}
+
+	if serverFilter != "" {
+		var filtered []health.CheckResult
+		for _, e := range entries {
+			if a.historyMatches(e.ServerName, serverFilter) {
+				filtered = append(filtered, e)
+			}
+		}
+		entries = filtered
+	}
+
+	report.Servers = history.Summarize(entries)
+
+	if jsonOutput {
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("error formatting json: %w", err)
+		}
+		return string(output), nil
+	}
+
+	return formatHistoryReport(report), nil
+}
+
+// historyMatches reports whether a recorded server name matches filter,
+// using canonical aliases and tags when the server still exists
+func (a *App) historyMatches(name, filter string) bool {
+	if srv := a.Canon.FindByName(name); srv != nil {
+		return matchesFilter(*srv, filter)
+	}
+	return strings.Contains(strings.ToLower(name), strings.ToLower(filter))
+}
+
+func formatHistoryReport(report HistoryReport) string {
+	var output strings.Builder
+
+	output.WriteString(style.Title("Health History"))
+	output.WriteString("\n")
+	output.WriteString(style.Muted(fmt.Sprintf("Since %s", report.Since.Format("2006-01-02 15:04:05"))) + "\n")
+
+	if len(report.Servers) == 0 {
+		output.WriteString("\n" + style.Muted("No health checks recorded in this period") + "\n")
+		return output.String()
+	}
+
+	var rows [][]string
+	for _, st := range report.Servers {
+		rate := fmt.Sprintf("%.0f%%", st.PassRate*100)
+		switch {
+		case st.Passed == st.Checks:
+			rate = "✓ " + rate
+		case st.PassRate >= 0.8:
+			rate = "⚠ " + rate
+		default:
+			rate = "✗ " + rate
+		}
+
+		lastFailure := style.Muted("none")
+		if !st.LastFailureAt.IsZero() {
+			lastFailure = fmt.Sprintf("%s %s", st.LastFailureAt.Format("01-02 15:04"), oneLine(st.LastFailure, 40))
+		}
+
+		rows = append(rows, []string{
+			st.Server,
+			fmt.Sprintf("%d", st.Checks),
+			rate,
+			st.P50.Round(time.Millisecond).String(),
+			st.P95.Round(time.Millisecond).String(),
+			lastFailure,
+		})
+	}
+
+	output.WriteString("\n")
+	output.WriteString(style.StatusTable(rows, []string{"Server", "Checks", "Pass Rate", "p50", "p95", "Last Failure"}))
+	return output.String()
+}
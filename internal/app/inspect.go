@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"mseep/internal/health"
+	"mseep/internal/inventory"
+	"mseep/internal/mcp"
+	"mseep/internal/pin"
+	"mseep/internal/style"
+)
+
+// Inspect lists the tools, prompts and resources of the server matching
+// query. With cached set, the last stored inventory is shown without
+// launching the server; otherwise the server is queried and the cache updated.
+func (a *App) Inspect(query string, cached bool, timeout time.Duration, jsonOutput bool) (string, error) {
+	srv, err := a.selectServer(query, false)
+	if err != nil {
+		return "", err
+	}
+
+	var inv *inventory.Inventory
+	if cached {
+		inv, err = inventory.Load(srv.Name)
+		if err != nil {
+			return "", err
+		}
+		if inv == nil {
+			return "", fmt.Errorf("no cached inventory for %q; run 'mseep inspect %s' first", srv.Name, query)
+		}
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		inv, err = inventory.Fetch(ctx, health.NewManager(), *srv)
+		if err != nil {
+			return "", fmt.Errorf("failed to inspect %q: %w", srv.Name, err)
+		}
+		if err := inventory.Save(inv); err != nil {
+			return "", fmt.Errorf("failed to cache inventory: %w", err)
+		}
+	}
+
+	drift, err := pinDrift(inv)
+	if err != nil {
+		return "", err
+	}
+
+	if jsonOutput {
+		report := struct {
+			*inventory.Inventory
+			Drift *pin.Drift `json:"toolDrift,omitempty"`
+		}{inv, drift}
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("error formatting json: %w", err)
+		}
+		return string(output), nil
+	}
+
+	output := formatInventory(inv)
+	if drift != nil {
+		output += "\n" + formatDrift(drift)
+	}
+	return output, nil
+}
+
+func formatInventory(inv *inventory.Inventory) string {
+	var output strings.Builder
+
+	output.WriteString(style.Title(fmt.Sprintf("Inventory: %s", inv.Server)))
+	output.WriteString("\n")
+	output.WriteString(style.Muted("Server: ") + fmt.Sprintf("%s %s", inv.ServerInfo.Name, inv.ServerInfo.Version) + "\n")
+	output.WriteString(style.Muted("Protocol: ") + inv.ProtocolVersion + "\n")
+
+	output.WriteString(style.Header(fmt.Sprintf("Tools (%d)", len(inv.Tools))))
+	if len(inv.Tools) > 0 {
+		var rows [][]string
+		for _, t := range inv.Tools {
+			rows = append(rows, []string{t.Name, oneLine(t.Description, 60)})
+		}
+		output.WriteString("\n" + style.StatusTable(rows, []string{"Tool", "Description"}))
+	} else {
+		output.WriteString(style.Muted("none") + "\n")
+	}
+
+	output.WriteString(style.Header(fmt.Sprintf("Prompts (%d)", len(inv.Prompts))))
+	if len(inv.Prompts) > 0 {
+		var rows [][]string
+		for _, p := range inv.Prompts {
+			rows = append(rows, []string{p.Name, promptArgs(p), oneLine(p.Description, 50)})
+		}
+		output.WriteString("\n" + style.StatusTable(rows, []string{"Prompt", "Arguments", "Description"}))
+	} else {
+		output.WriteString(style.Muted("none") + "\n")
+	}
+
+	output.WriteString(style.Header(fmt.Sprintf("Resources (%d)", len(inv.Resources))))
+	if len(inv.Resources) > 0 {
+		var rows [][]string
+		for _, r := range inv.Resources {
+			rows = append(rows, []string{r.URI, r.Name, r.MimeType})
+		}
+		output.WriteString("\n" + style.StatusTable(rows, []string{"URI", "Name", "MIME Type"}))
+	} else {
+		output.WriteString(style.Muted("none") + "\n")
+	}
+
+	output.WriteString("\n" + style.Muted(fmt.Sprintf("Fetched at %s", inv.FetchedAt.Format("2006-01-02 15:04:05"))) + "\n")
+	return output.String()
+}
+
+func promptArgs(p mcp.Prompt) string {
+	var args []string
+	for _, arg := range p.Arguments {
+		if arg.Required {
+			args = append(args, arg.Name+"*")
+		} else {
+			args = append(args, arg.Name)
+		}
+	}
+	return strings.Join(args, ", ")
+}
+
+// oneLine collapses whitespace and truncates s to n characters for tables
+func oneLine(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	r := []rune(s)
+	if len(r) > n {
+		return string(r[:n-3]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,252 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"mseep/internal/adapters/claude"
+	"mseep/internal/health"
+	"mseep/internal/history"
+	"mseep/internal/style"
+)
+
+// ServerLogs collects a server's captured check output and client logs
+type ServerLogs struct {
+	Server     string              `json:"server"`
+	LastCheck  *health.CheckResult `json:"lastCheck,omitempty"`
+	ClientLogs []ClientLog         `json:"clientLogs"`
+}
+
+// ClientLog is the tail of a client's own log for a server
+type ClientLog struct {
+	Client  string    `json:"client"`
+	Path    string    `json:"path"`
+	ModTime time.Time `json:"modTime"`
+	Lines   []string  `json:"lines"`
+}
+
+// clientLogPath is a log file a client writes for a server
+type clientLogPath struct {
+	client string
+	path   string
+}
+
+// clientLogPaths lists the log files clients write for server
+func clientLogPaths(server string) []clientLogPath {
+	var paths []clientLogPath
+	if p, err := (claude.Adapter{}).LogPath(server); err == nil {
+		paths = append(paths, clientLogPath{client: "claude", path: p})
+	}
+	return paths
+}
+
+// ServerLogs returns the most recent check with captured output and the
+// last lines of each existing client log for server
+func (a *App) ServerLogs(server string, lines int) (ServerLogs, error) {
+	logs := ServerLogs{Server: server, ClientLogs: []ClientLog{}}
+
+	store, err := history.Open()
+	if err != nil {
+		return logs, err
+	}
+	entries, err := store.Query(server, time.Time{})
+	if err != nil {
+		return logs, fmt.Errorf("failed to read health history: %w", err)
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Stdout != "" || entries[i].Stderr != "" {
+			logs.LastCheck = &entries[i]
+			break
+		}
+	}
+
+	for _, cl := range clientLogPaths(server) {
+		fi, err := os.Stat(cl.path)
+		if err != nil {
+			continue
+		}
+		tail, err := tailFile(cl.path, lines)
+		if err != nil {
+			return logs, err
+		}
+		logs.ClientLogs = append(logs.ClientLogs, ClientLog{Client: cl.client, Path: cl.path, ModTime: fi.ModTime(), Lines: tail})
+	}
+	return logs, nil
+}
+
+// Logs shows the captured output of a server's last failed check and the
+// tail of its client logs
+func (a *App) Logs(query string, lines int, jsonOutput bool) (string, error) {
+	srv, err := a.selectServer(query, true)
+	if err != nil {
+		return "", err
+	}
+	logs, err := a.ServerLogs(srv.Name, lines)
+	if err != nil {
+		return "", err
+	}
+
+	if jsonOutput {
+		output, err := json.MarshalIndent(logs, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("error formatting json: %w", err)
+		}
+		return string(output), nil
+	}
+
+	return formatServerLogs(logs, lines), nil
+}
+
+// FollowLogs streams lines appended to server's client logs to w until
+// ctx is cancelled
+func (a *App) FollowLogs(ctx context.Context, query string, w io.Writer) error {
+	srv, err := a.selectServer(query, true)
+	if err != nil {
+		return err
+	}
+	paths := clientLogPaths(srv.Name)
+	offsets := make([]int64, len(paths))
+	for i, cl := range paths {
+		if fi, err := os.Stat(cl.path); err == nil {
+			offsets[i] = fi.Size()
+		}
+		fmt.Fprintln(w, style.Muted(fmt.Sprintf("Following %s (Ctrl-C to stop)", cl.path)))
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		for i, cl := range paths {
+			off, err := readFrom(cl.path, offsets[i], func(line string) {
+				fmt.Fprintf(w, "%s %s\n", style.Muted("["+cl.client+"]"), line)
+			})
+			if err != nil {
+				continue
+			}
+			offsets[i] = off
+		}
+	}
+}
+
+// readFrom emits complete lines written to path after offset and returns
+// the new offset. A file that shrank was rotated and is read from the start.
+func readFrom(path string, offset int64, emit func(string)) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return offset, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return offset, err
+	}
+	if fi.Size() < offset {
+		offset = 0
+	}
+	if fi.Size() == offset {
+		return offset, nil
+	}
+	b := make([]byte, fi.Size()-offset)
+	n, err := f.ReadAt(b, offset)
+	if err != nil && err != io.EOF {
+		return offset, err
+	}
+	b = b[:n]
+	// Leave a partial last line for the next read
+	end := bytes.LastIndexByte(b, '\n')
+	if end < 0 {
+		return offset, nil
+	}
+	for _, line := range strings.Split(string(b[:end]), "\n") {
+		emit(line)
+	}
+	return offset + int64(end) + 1, nil
+}
+
+// tailFile returns the last n lines of path, reading at most 64KB
+func tailFile(path string, n int) ([]string, error) {
+	const window = 64 * 1024
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	start := fi.Size() - window
+	if start < 0 {
+		start = 0
+	}
+	b := make([]byte, fi.Size()-start)
+	if _, err := f.ReadAt(b, start); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return lastLines(string(b), n), nil
+}
+
+// lastLines returns the last n lines of s, ignoring a trailing newline
+func lastLines(s string, n int) []string {
+	s = strings.TrimRight(s, "\n")
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(s, "\n")
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+func formatServerLogs(logs ServerLogs, lines int) string {
+	var output strings.Builder
+
+	output.WriteString(style.Title("Logs: " + logs.Server))
+	output.WriteString("\n")
+
+	if logs.LastCheck == nil && len(logs.ClientLogs) == 0 {
+		output.WriteString(style.Muted("No captured output or client logs. Failed 'mseep health' checks capture server output.") + "\n")
+		return output.String()
+	}
+
+	if c := logs.LastCheck; c != nil {
+		output.WriteString(style.Header("Last Failed Check") + "\n")
+		output.WriteString(style.Muted(fmt.Sprintf("%s  %s: %s", c.Timestamp.Format("2006-01-02 15:04:05"), c.Status, c.Message)) + "\n")
+		if c.Stderr != "" {
+			output.WriteString("\n" + style.Muted("stderr:") + "\n")
+			output.WriteString(indentLines(lastLines(c.Stderr, lines)))
+		}
+		if c.Stdout != "" {
+			output.WriteString("\n" + style.Muted("stdout:") + "\n")
+			output.WriteString(indentLines(lastLines(c.Stdout, lines)))
+		}
+	}
+
+	for _, cl := range logs.ClientLogs {
+		output.WriteString("\n" + style.Header(fmt.Sprintf("%s log", cl.Client)) + "\n")
+		output.WriteString(style.Muted(fmt.Sprintf("%s (updated %s)", cl.Path, cl.ModTime.Format("2006-01-02 15:04:05"))) + "\n")
+		output.WriteString(indentLines(cl.Lines))
+	}
+
+	return output.String()
+}
+
+func indentLines(lines []string) string {
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString("  " + l + "\n")
+	}
+	return b.String()
+}
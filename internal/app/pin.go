@@ -0,0 +1,200 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"mseep/internal/health"
+	"mseep/internal/inventory"
+	"mseep/internal/pin"
+	"mseep/internal/style"
+)
+
+// pinFetchTimeout bounds the tools/list fetch made to verify a pin
+const pinFetchTimeout = 30 * time.Second
+
+// PinTools fetches the current tool definitions of the server matching
+// query and, once approved, records them as the pinned baseline.
+func (a *App) PinTools(query string, assumeYes bool, timeout time.Duration) (string, error) {
+	srv, err := a.selectServer(query, assumeYes)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	inv, err := inventory.Fetch(ctx, health.NewManager(), *srv)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch tools for %q: %w", srv.Name, err)
+	}
+	if err := inventory.Save(inv); err != nil {
+		return "", fmt.Errorf("failed to cache inventory: %w", err)
+	}
+
+	store, err := pin.Load()
+	if err != nil {
+		return "", err
+	}
+
+	// Show what is being approved: the change since the old pin, or every tool
+	if old, ok := store.Pins[srv.Name]; ok {
+		drift := old.Compare(inv.Tools)
+		if drift.Empty() {
+			return style.Success(fmt.Sprintf("Tools for %q already match the approved pin", srv.Name)) + "\n", nil
+		}
+		fmt.Print(formatDrift(drift))
+	} else {
+		fmt.Print(style.Header(fmt.Sprintf("Tools exposed by %s (%d)", srv.Name, len(inv.Tools))) + "\n")
+		for _, t := range inv.Tools {
+			fmt.Print(style.ListItem(t.Name) + "\n")
+			if t.Description != "" {
+				fmt.Print(style.Muted("    "+strings.ReplaceAll(t.Description, "\n", "\n    ")) + "\n")
+			}
+		}
+	}
+
+	if !assumeYes {
+		fmt.Print("\nApprove these tool definitions? [y/N]: ")
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return "", fmt.Errorf("failed to read response: %w", err)
+		}
+
+		response = strings.ToLower(strings.TrimSpace(response))
+		if response != "y" && response != "yes" {
+			return style.Warning("Tools not pinned") + "\n", nil
+		}
+	}
+
+	store.Pins[srv.Name] = pin.New(srv.Name, inv.Tools)
+	if err := store.Save(); err != nil {
+		return "", fmt.Errorf("failed to save pins: %w", err)
+	}
+	return style.Success(fmt.Sprintf("Pinned %d tools for %q", len(inv.Tools), srv.Name)) + "\n", nil
+}
+
+// Unpin removes the approved tool pin of the server matching query
+func (a *App) Unpin(query string) (string, error) {
+	srv, err := a.selectServer(query, false)
+	if err != nil {
+		return "", err
+	}
+	store, err := pin.Load()
+	if err != nil {
+		return "", err
+	}
+	if _, ok := store.Pins[srv.Name]; !ok {
+		return "", fmt.Errorf("server %q has no pinned tools", srv.Name)
+	}
+	delete(store.Pins, srv.Name)
+	if err := store.Save(); err != nil {
+		return "", fmt.Errorf("failed to save pins: %w", err)
+	}
+	return style.Success(fmt.Sprintf("Removed tool pin for %q", srv.Name)) + "\n", nil
+}
+
+// PinFailure is a pinned server whose tools could not be fetched, so its
+// pin could not be verified
+type PinFailure struct {
+	Server string `json:"server"`
+	Error  string `json:"error"`
+}
+
+// verifyPins fetches the tools of every healthy pinned server and reports
+// the ones whose tool set changed since approval and the ones whose tools
+// could not be fetched
+func (a *App) verifyPins(ctx context.Context, mgr *health.Manager, results []health.CheckResult) ([]*pin.Drift, []PinFailure, error) {
+	store, err := pin.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(store.Pins) == 0 {
+		return nil, nil, nil
+	}
+
+	var drifts []*pin.Drift
+	var unverified []PinFailure
+	for _, result := range results {
+		p, ok := store.Pins[result.ServerName]
+		if !ok || result.Status != health.StatusHealthy {
+			continue
+		}
+		srv := a.Canon.FindByName(result.ServerName)
+		if srv == nil {
+			continue
+		}
+
+		fetchCtx, cancel := context.WithTimeout(ctx, pinFetchTimeout)
+		inv, err := inventory.Fetch(fetchCtx, mgr, *srv)
+		cancel()
+		if err != nil {
+			unverified = append(unverified, PinFailure{Server: srv.Name, Error: err.Error()})
+			continue
+		}
+		if err := inventory.Save(inv); err != nil {
+			return nil, nil, fmt.Errorf("failed to cache inventory for %q: %w", srv.Name, err)
+		}
+
+		if d := p.Compare(inv.Tools); !d.Empty() {
+			drifts = append(drifts, d)
+		}
+	}
+	return drifts, unverified, nil
+}
+
+// pinDrift compares an inventory against its pin, if the server has one
+func pinDrift(inv *inventory.Inventory) (*pin.Drift, error) {
+	store, err := pin.Load()
+	if err != nil {
+		return nil, err
+	}
+	p, ok := store.Pins[inv.Server]
+	if !ok {
+		return nil, nil
+	}
+	if d := p.Compare(inv.Tools); !d.Empty() {
+		return d, nil
+	}
+	return nil, nil
+}
+
+func formatDrift(d *pin.Drift) string {
+	var output strings.Builder
+
+	output.WriteString(style.Warning(fmt.Sprintf("%s: tools changed since approval", d.Server)) + "\n")
+	for _, name := range d.Added {
+		output.WriteString(style.ListItem("added: "+name) + "\n")
+	}
+	for _, name := range d.Removed {
+		output.WriteString(style.ListItem("removed: "+name) + "\n")
+	}
+	for _, c := range d.Changed {
+		if c.SchemaChanged {
+			output.WriteString(style.ListItem("input schema changed: "+c.Name) + "\n")
+		}
+		if c.OldDescription != c.NewDescription {
+			output.WriteString(style.ListItem("description changed: "+c.Name) + "\n")
+			output.WriteString(style.DiffBox(descriptionDiff(c.OldDescription, c.NewDescription)) + "\n")
+		}
+	}
+	return output.String()
+}
+
+// descriptionDiff shows the approved description as removed lines and the
+// current one as added lines, which reads better than a character diff
+func descriptionDiff(before, after string) string {
+	var lines []string
+	for _, l := range strings.Split(before, "\n") {
+		lines = append(lines, "- "+l)
+	}
+	for _, l := range strings.Split(after, "\n") {
+		lines = append(lines, "+ "+l)
+	}
+	return strings.Join(lines, "\n")
+}
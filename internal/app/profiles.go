@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"mseep/internal/config"
+	"mseep/internal/launchenv"
 	"mseep/internal/style"
 )
 
@@ -36,31 +37,29 @@ func (a *App) ListProfiles(jsonOutput bool) (string, error) {
 	sort.Strings(names)
 
 	for _, name := range names {
-		servers := a.Canon.Profiles[name]
 		output.WriteString("\n")
 		output.WriteString(style.Header(name))
-		
-		if len(servers) == 0 {
+
+		resolved, err := a.Canon.ResolveProfile(name)
+		if err != nil {
+			output.WriteString(style.Error(err.Error()) + "\n")
+			continue
+		}
+		if desc := describeProfile(a.Canon.Profiles[name]); desc != "" {
+			output.WriteString(style.Muted("  "+desc) + "\n")
+		}
+
+		if len(resolved.Members) == 0 {
 			output.WriteString(style.Muted("  (no servers)") + "\n")
 		} else {
-			output.WriteString(style.Muted(fmt.Sprintf("  %d servers:", len(servers))) + "\n")
-			for _, serverName := range servers {
-				// Check if server exists
-				exists := false
-				for _, srv := range a.Canon.Servers {
-					if srv.Name == serverName {
-						exists = true
-						break
-					}
-				}
-				
-				if exists {
-					output.WriteString(style.ListItem(serverName) + "\n")
-				} else {
-					output.WriteString(style.Warning(fmt.Sprintf("• %s (server not found)", serverName)) + "\n")
-				}
+			output.WriteString(style.Muted(fmt.Sprintf("  %d servers:", len(resolved.Members))) + "\n")
+			for _, serverName := range resolved.Names() {
+				output.WriteString(style.ListItem(serverName) + "\n")
 			}
 		}
+		for _, serverName := range resolved.Missing {
+			output.WriteString(style.Warning(fmt.Sprintf("• %s (server not found)", serverName)) + "\n")
+		}
 	}
 
 	output.WriteString("\n" + style.Muted(fmt.Sprintf("Total: %d profiles", len(a.Canon.Profiles))) + "\n")
@@ -68,14 +67,14 @@ func (a *App) ListProfiles(jsonOutput bool) (string, error) {
 }
 
 // CreateProfile creates a new profile
-func (a *App) CreateProfile(name string, serverNames []string) error {
+func (a *App) CreateProfile(name string, profile config.Profile) error {
 	if name == "" {
 		return fmt.Errorf("profile name cannot be empty")
 	}
 
 	// Initialize profiles map if nil
 	if a.Canon.Profiles == nil {
-		a.Canon.Profiles = make(map[string][]string)
+		a.Canon.Profiles = make(map[string]config.Profile)
 	}
 
 	// Check if profile already exists
@@ -83,22 +82,11 @@ func (a *App) CreateProfile(name string, serverNames []string) error {
 		return fmt.Errorf("profile %q already exists", name)
 	}
 
-	// Validate that all servers exist
-	for _, serverName := range serverNames {
-		found := false
-		for _, srv := range a.Canon.Servers {
-			if srv.Name == serverName {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return fmt.Errorf("server %q not found", serverName)
-		}
-	}
-
 	// Create the profile
-	a.Canon.Profiles[name] = serverNames
+	if err := a.setProfile(name, profile); err != nil {
+		delete(a.Canon.Profiles, name)
+		return err
+	}
 
 	// Save configuration
 	if err := config.Save("", a.Canon); err != nil {
@@ -128,32 +116,196 @@ func (a *App) DeleteProfile(name string) error {
 	return nil
 }
 
-// UpdateProfile updates an existing profile
-func (a *App) UpdateProfile(name string, serverNames []string) error {
-	if a.Canon.Profiles == nil {
-		a.Canon.Profiles = make(map[string][]string)
+// RenameProfile renames a profile, updating the profiles that extend it
+func (a *App) RenameProfile(old, new string) ([]string, error) {
+	changed, err := a.Canon.RenameProfile(old, new)
+	if err != nil {
+		return nil, err
 	}
 
-	if _, exists := a.Canon.Profiles[name]; !exists {
-		return fmt.Errorf("profile %q not found", name)
+	// Save configuration
+	if err := config.Save("", a.Canon); err != nil {
+		return nil, fmt.Errorf("failed to save configuration: %w", err)
 	}
 
-	// Validate that all servers exist
-	for _, serverName := range serverNames {
-		found := false
-		for _, srv := range a.Canon.Servers {
-			if srv.Name == serverName {
-				found = true
-				break
+	return changed, nil
+}
+
+// CopyProfile creates profile dst with the definition of src
+func (a *App) CopyProfile(src, dst string) error {
+	p, ok := a.Canon.Profiles[src]
+	if !ok {
+		return fmt.Errorf("profile %q not found", src)
+	}
+	return a.CreateProfile(dst, copyProfile(p))
+}
+
+// AddToProfile adds servers, found by fuzzy query, or selectors to a
+// profile's includes. A server the profile excluded is no longer excluded.
+func (a *App) AddToProfile(name string, queries []string, assumeYes bool) (string, error) {
+	current, ok := a.Canon.Profiles[name]
+	if !ok {
+		return "", fmt.Errorf("profile %q not found", name)
+	}
+	p := copyProfile(current)
+
+	var output strings.Builder
+	for _, q := range queries {
+		entry, err := a.profileEntry(q, assumeYes)
+		if err != nil {
+			return "", err
+		}
+		excluded := contains(p.Exclude, entry)
+		p.Exclude = without(p.Exclude, entry)
+
+		// A server inherited or matched once it is no longer excluded
+		// needs no include of its own
+		member := contains(p.Include, entry)
+		if !member && !config.IsSelector(entry) {
+			resolved, err := a.resolveWith(name, p)
+			if err != nil {
+				return "", err
 			}
+			member = resolved.Has(entry)
+		}
+		if member && !excluded {
+			output.WriteString(style.Muted(fmt.Sprintf("%s is already in %s", entry, name)) + "\n")
+			continue
 		}
-		if !found {
-			return fmt.Errorf("server %q not found", serverName)
+		if !member {
+			p.Include = append(p.Include, entry)
 		}
+		output.WriteString(style.Success(fmt.Sprintf("Added %s to %s", entry, name)) + "\n")
+	}
+
+	if err := a.UpdateProfile(name, p); err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
+
+// RemoveFromProfile removes servers, found by fuzzy query, or selectors
+// from a profile. A server that would still be inherited from a parent or
+// matched by a selector is excluded instead.
+func (a *App) RemoveFromProfile(name string, queries []string, assumeYes bool) (string, error) {
+	current, ok := a.Canon.Profiles[name]
+	if !ok {
+		return "", fmt.Errorf("profile %q not found", name)
+	}
+	p := copyProfile(current)
+
+	var output strings.Builder
+	for _, q := range queries {
+		entry, err := a.profileEntry(q, assumeYes)
+		if err != nil {
+			return "", err
+		}
+		listed := contains(p.Include, entry)
+		p.Include = without(p.Include, entry)
+		if tag, ok := strings.CutPrefix(entry, config.TagPrefix); ok && contains(p.Tags, tag) {
+			p.Tags, listed = without(p.Tags, tag), true
+		}
+		if query, ok := strings.CutPrefix(entry, config.QueryPrefix); ok && p.Query == query {
+			p.Query, listed = "", true
+		}
+
+		if !config.IsSelector(entry) {
+			resolved, err := a.resolveWith(name, p)
+			if err != nil {
+				return "", err
+			}
+			if resolved.Has(entry) {
+				p.Exclude = append(p.Exclude, entry)
+				output.WriteString(style.Success(fmt.Sprintf("Excluded %s from %s", entry, name)) + "\n")
+				continue
+			}
+		}
+		if !listed {
+			output.WriteString(style.Muted(fmt.Sprintf("%s is not in %s", entry, name)) + "\n")
+			continue
+		}
+		output.WriteString(style.Success(fmt.Sprintf("Removed %s from %s", entry, name)) + "\n")
+	}
+
+	if err := a.UpdateProfile(name, p); err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
+
+// profileEntry returns the include or exclude entry for a query: a
+// selector as it is, otherwise the name of the best fuzzy match
+func (a *App) profileEntry(query string, assumeYes bool) (string, error) {
+	if config.IsSelector(query) {
+		return query, nil
+	}
+	srv, err := a.selectServer(query, assumeYes)
+	if err != nil {
+		return "", err
+	}
+	return srv.Name, nil
+}
+
+// resolveWith resolves profile name as if it were defined as p
+func (a *App) resolveWith(name string, p config.Profile) (*config.ResolvedProfile, error) {
+	canon := *a.Canon
+	canon.Profiles = make(map[string]config.Profile, len(a.Canon.Profiles))
+	for n, prof := range a.Canon.Profiles {
+		canon.Profiles[n] = prof
+	}
+	canon.Profiles[name] = p
+	return canon.ResolveProfile(name)
+}
+
+func copyProfile(p config.Profile) config.Profile {
+	p.Extends = append([]string(nil), p.Extends...)
+	p.Include = append([]string(nil), p.Include...)
+	p.Exclude = append([]string(nil), p.Exclude...)
+	p.Tags = append([]string(nil), p.Tags...)
+	p.Clients = append([]string(nil), p.Clients...)
+	if p.Schedule != nil {
+		s := *p.Schedule
+		s.Days = append([]string(nil), s.Days...)
+		p.Schedule = &s
+	}
+	return p
+}
+
+func contains(list []string, v string) bool {
+	for _, e := range list {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func without(list []string, v string) []string {
+	var out []string
+	for _, e := range list {
+		if e != v {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// UpdateProfile updates an existing profile
+func (a *App) UpdateProfile(name string, profile config.Profile) error {
+	if a.Canon.Profiles == nil {
+		a.Canon.Profiles = make(map[string]config.Profile)
+	}
+
+	previous, exists := a.Canon.Profiles[name]
+	if !exists {
+		return fmt.Errorf("profile %q not found", name)
 	}
 
 	// Update the profile
-	a.Canon.Profiles[name] = serverNames
+	if err := a.setProfile(name, profile); err != nil {
+		a.Canon.Profiles[name] = previous
+		return err
+	}
 
 	// Save configuration
 	if err := config.Save("", a.Canon); err != nil {
@@ -163,6 +315,41 @@ func (a *App) UpdateProfile(name string, serverNames []string) error {
 	return nil
 }
 
+// setProfile stores profile under name after checking that its servers
+// and parents exist and that it resolves without a cycle. Selectors may
+// match nothing yet.
+func (a *App) setProfile(name string, profile config.Profile) error {
+	for _, rule := range append(append([]string{}, profile.Include...), profile.Exclude...) {
+		if config.IsSelector(rule) {
+			continue
+		}
+		if a.Canon.FindByName(rule) == nil {
+			return fmt.Errorf("server %q not found", rule)
+		}
+	}
+	for _, c := range profile.Clients {
+		if !knownClient(c) {
+			return fmt.Errorf("unknown client %q (want %s)", c, strings.Join(launchenv.Clients, ", "))
+		}
+	}
+	for _, parent := range profile.Extends {
+		if _, ok := a.Canon.Profiles[parent]; !ok {
+			return fmt.Errorf("profile %q not found", parent)
+		}
+	}
+	if profile.Schedule != nil {
+		if err := profile.Schedule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	a.Canon.Profiles[name] = profile
+	if _, err := a.Canon.ResolveProfile(name); err != nil {
+		return err
+	}
+	return nil
+}
+
 // CreateProfileFromCurrent creates a profile from currently enabled servers
 func (a *App) CreateProfileFromCurrent(name string) error {
 	if name == "" {
@@ -171,7 +358,7 @@ func (a *App) CreateProfileFromCurrent(name string) error {
 
 	// Initialize profiles map if nil
 	if a.Canon.Profiles == nil {
-		a.Canon.Profiles = make(map[string][]string)
+		a.Canon.Profiles = make(map[string]config.Profile)
 	}
 
 	// Check if profile already exists
@@ -182,7 +369,7 @@ func (a *App) CreateProfileFromCurrent(name string) error {
 	// Collect enabled servers
 	var enabledServers []string
 	for _, srv := range a.Canon.Servers {
-		if srv.Enabled {
+		if srv.EnabledAnywhere() {
 			enabledServers = append(enabledServers, srv.Name)
 		}
 	}
@@ -192,7 +379,7 @@ func (a *App) CreateProfileFromCurrent(name string) error {
 	}
 
 	// Create the profile
-	a.Canon.Profiles[name] = enabledServers
+	a.Canon.Profiles[name] = config.Profile{Include: enabledServers}
 
 	// Save configuration
 	if err := config.Save("", a.Canon); err != nil {
@@ -200,4 +387,191 @@ func (a *App) CreateProfileFromCurrent(name string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// ShowProfile prints the fully resolved server set of a profile and where
+// each server came from
+func (a *App) ShowProfile(name string, jsonOutput bool) (string, error) {
+	resolved, err := a.Canon.ResolveProfile(name)
+	if err != nil {
+		return "", err
+	}
+
+	if jsonOutput {
+		output, err := json.MarshalIndent(resolved, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("error formatting json: %w", err)
+		}
+		return string(output), nil
+	}
+
+	var output strings.Builder
+	output.WriteString(style.Title("Profile: " + name))
+	output.WriteString("\n")
+	if desc := describeProfile(a.Canon.Profiles[name]); desc != "" {
+		output.WriteString(style.Muted(desc) + "\n")
+	}
+
+	if len(resolved.Members) == 0 {
+		output.WriteString("\n" + style.Muted("(no servers)") + "\n")
+	} else {
+		var rows [][]string
+		for _, m := range resolved.Members {
+			rows = append(rows, []string{m.Server, memberSource(name, m)})
+		}
+		output.WriteString("\n")
+		output.WriteString(style.StatusTable(rows, []string{"Server", "Source"}))
+	}
+
+	for _, m := range resolved.Excluded {
+		by := m.Profile
+		if config.IsSelector(m.Rule) {
+			by += " (" + m.Rule + ")"
+		}
+		output.WriteString(style.Muted(fmt.Sprintf("- %s excluded by %s", m.Server, by)) + "\n")
+	}
+	for _, serverName := range resolved.Missing {
+		output.WriteString(style.Warning(fmt.Sprintf("%s (server not found)", serverName)) + "\n")
+	}
+
+	output.WriteString("\n" + style.Muted(fmt.Sprintf("Total: %d servers", len(resolved.Members))) + "\n")
+	return output.String(), nil
+}
+
+// memberSource describes the rule that added a server, naming
+// the parent profile when it was inherited
+func memberSource(profile string, m config.ProfileMember) string {
+	rule := m.Rule
+	if !config.IsSelector(rule) {
+		rule = "listed"
+	}
+	if m.Profile == profile {
+		return rule
+	}
+	return fmt.Sprintf("%s (via %s)", rule, m.Profile)
+}
+
+// describeProfile summarizes a profile definition on one line
+func describeProfile(p config.Profile) string {
+	var parts []string
+	if len(p.Extends) > 0 {
+		parts = append(parts, "extends "+strings.Join(p.Extends, ", "))
+	}
+	var selectors []string
+	for _, rule := range p.Include {
+		if config.IsSelector(rule) {
+			selectors = append(selectors, rule)
+		}
+	}
+	for _, t := range p.Tags {
+		selectors = append(selectors, config.TagPrefix+t)
+	}
+	if p.Query != "" {
+		selectors = append(selectors, config.QueryPrefix+p.Query)
+	}
+	if len(selectors) > 0 {
+		parts = append(parts, "includes "+strings.Join(selectors, ", "))
+	}
+	if len(p.Exclude) > 0 {
+		parts = append(parts, "excludes "+strings.Join(p.Exclude, ", "))
+	}
+	if len(p.Clients) > 0 {
+		parts = append(parts, "for "+strings.Join(p.Clients, ", "))
+	}
+	if p.Schedule != nil {
+		parts = append(parts, "scheduled "+p.Schedule.String())
+	}
+	return strings.Join(parts, " · ")
+}
+
+// ProfileDiff compares the server sets of two profiles, or of a profile
+// and the currently enabled servers
+type ProfileDiff struct {
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+	Unchanged []string `json:"unchanged"`
+}
+
+// DiffProfiles shows what changes going from profile 'from' (or "current",
+// the enabled servers) to profile 'to', i.e. what applying 'to' would do
+func (a *App) DiffProfiles(to, from string, jsonOutput bool) (string, error) {
+	if from == "" {
+		from = "current"
+	}
+	toSet, err := a.profileSet(to)
+	if err != nil {
+		return "", err
+	}
+	fromSet, err := a.profileSet(from)
+	if err != nil {
+		return "", err
+	}
+
+	d := ProfileDiff{From: from, To: to, Added: []string{}, Removed: []string{}, Unchanged: []string{}}
+	for _, srv := range a.Canon.Servers {
+		switch inTo, inFrom := toSet[srv.Name], fromSet[srv.Name]; {
+		case inTo && inFrom:
+			d.Unchanged = append(d.Unchanged, srv.Name)
+		case inTo:
+			d.Added = append(d.Added, srv.Name)
+		case inFrom:
+			d.Removed = append(d.Removed, srv.Name)
+		}
+	}
+
+	if jsonOutput {
+		output, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("error formatting json: %w", err)
+		}
+		return string(output), nil
+	}
+
+	var output strings.Builder
+	output.WriteString(style.Title(fmt.Sprintf("Profile Diff: %s → %s", from, to)))
+	output.WriteString("\n")
+	for _, name := range d.Added {
+		output.WriteString(style.Added(name) + "\n")
+	}
+	for _, name := range d.Removed {
+		output.WriteString(style.Removed(name) + "\n")
+	}
+	for _, name := range d.Unchanged {
+		output.WriteString(style.Muted("  "+name) + "\n")
+	}
+	if len(d.Added) == 0 && len(d.Removed) == 0 {
+		output.WriteString(style.Muted("No differences") + "\n")
+	}
+
+	output.WriteString("\n" + style.Muted(fmt.Sprintf("%d added, %d removed, %d unchanged", len(d.Added), len(d.Removed), len(d.Unchanged))) + "\n")
+	if from == "current" && len(d.Removed) > 0 {
+		output.WriteString(style.Muted(fmt.Sprintf("Applying with --mode %s keeps the removed servers enabled", ModeAdditive)) + "\n")
+	}
+	return output.String(), nil
+}
+
+// profileSet returns the server names of a profile, or the servers enabled
+// for any client for "current"
+func (a *App) profileSet(name string) (map[string]bool, error) {
+	set := map[string]bool{}
+	if name == "current" {
+		if _, exists := a.Canon.Profiles[name]; !exists {
+			for _, srv := range a.Canon.Servers {
+				if srv.EnabledAnywhere() {
+					set[srv.Name] = true
+				}
+			}
+			return set, nil
+		}
+	}
+	resolved, err := a.Canon.ResolveProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range resolved.Names() {
+		set[n] = true
+	}
+	return set, nil
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"mseep/internal/config"
+	"mseep/internal/style"
+)
+
+// Reconcile disables expired temporary enables and brings the servers of
+// scheduled profiles in line with their windows, then applies the result
+// to the detected clients. With dryRun the changes are only listed.
+func (a *App) Reconcile(dryRun, autoApprove bool) error {
+	changes, err := a.Canon.Reconcile(time.Now())
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Print(style.Muted("Nothing to reconcile") + "\n")
+		return nil
+	}
+
+	for _, c := range changes {
+		fmt.Print(formatChange(c) + "\n")
+	}
+	if dryRun {
+		fmt.Print(style.Muted("Dry run: nothing saved") + "\n")
+		return nil
+	}
+	if err := config.Save("", a.Canon); err != nil {
+		return fmt.Errorf("failed to save canonical config: %w", err)
+	}
+	if len(detectedClients()) == 0 {
+		return nil
+	}
+	return a.Apply("", "", autoApprove)
+}
+
+func formatChange(c config.Change) string {
+	what := "disabled"
+	if c.Enabled {
+		what = "enabled"
+	}
+	if c.Client != "" {
+		what += " for " + c.Client
+	}
+	line := fmt.Sprintf("%s %s: %s", c.Server, what, c.Reason)
+	if c.Enabled {
+		return style.Success(line)
+	}
+	return style.Warning(line)
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"mseep/internal/adapters/claude"
+	"mseep/internal/adapters/cursor"
+	"mseep/internal/config"
+	"mseep/internal/pin"
+	"mseep/internal/style"
+)
+
+// RenameServer renames the server matching query, updates the profiles
+// and pin that refer to it, and re-applies to the detected clients so
+// their entries follow the new name
+func (a *App) RenameServer(query, newName string, assumeYes bool) error {
+	srv, err := a.selectServer(query, assumeYes)
+	if err != nil {
+		return err
+	}
+	old := srv.Name
+	profiles, err := a.Canon.RenameServer(old, newName)
+	if err != nil {
+		return err
+	}
+	if err := config.Save("", a.Canon); err != nil {
+		return fmt.Errorf("failed to save canonical config: %w", err)
+	}
+
+	store, err := pin.Load()
+	if err != nil {
+		return err
+	}
+	if p, ok := store.Pins[old]; ok {
+		p.Server = newName
+		store.Pins[newName] = p
+		delete(store.Pins, old)
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to update pins: %w", err)
+		}
+	}
+
+	// Claude and Cursor keep entries mseep does not manage, which the old
+	// name now is, so move them before applying
+	if err := renameClientEntries(old, newName); err != nil {
+		return err
+	}
+
+	fmt.Print(style.Success(fmt.Sprintf("Renamed %s to %s", old, newName)) + "\n")
+	if len(profiles) > 0 {
+		fmt.Print(style.Muted("  updated profiles: "+strings.Join(profiles, ", ")) + "\n")
+	}
+	if len(detectedClients()) == 0 {
+		return nil
+	}
+	return a.Apply("", "", assumeYes)
+}
+
+// renameClientEntries moves the Claude Desktop and Cursor entries of a
+// renamed server to its new name
+func renameClientEntries(old, newName string) error {
+	ca := claude.Adapter{}
+	if detectClient(ca) {
+		cfg, err := ca.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load Claude config: %w", err)
+		}
+		if srv, ok := cfg.MCPServers[old]; ok {
+			delete(cfg.MCPServers, old)
+			if _, taken := cfg.MCPServers[newName]; !taken {
+				cfg.MCPServers[newName] = srv
+			}
+			b, _ := json.MarshalIndent(cfg, "", "  ")
+			if err := writeClientConfig(ca, b); err != nil {
+				return fmt.Errorf("failed to update Claude config: %w", err)
+			}
+		}
+	}
+
+	cu := cursor.Adapter{}
+	if detectClient(cu) {
+		cfg, err := cu.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load Cursor config: %w", err)
+		}
+		if srv, ok := cfg.MCPServers[old]; ok {
+			delete(cfg.MCPServers, old)
+			if _, taken := cfg.MCPServers[newName]; !taken {
+				cfg.MCPServers[newName] = srv
+			}
+			settings := map[string]interface{}{}
+			for key, value := range cfg.Other {
+				settings[key] = value
+			}
+			if len(cfg.MCPServers) > 0 {
+				settings["mcp.servers"] = cfg.MCPServers
+			}
+			b, _ := json.MarshalIndent(settings, "", "  ")
+			if err := writeClientConfig(cu, b); err != nil {
+				return fmt.Errorf("failed to update Cursor config: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// writeClientConfig backs up a client's config and replaces it with b
+func writeClientConfig(adapter interface {
+	Backup() (string, error)
+	Path() (string, error)
+}, b []byte) error {
+	if _, err := adapter.Backup(); err != nil {
+		return err
+	}
+	p, err := adapter.Path()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, b, 0o644)
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mseep/internal/adapters/claude"
+	"mseep/internal/config"
+)
+
+func TestRenameServerMovesClaudeEntry(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	fixture := claude.ClaudeConfig{MCPServers: map[string]claude.ClaudeServer{
+		"github":    {Command: "gh-mcp"},
+		"unmanaged": {Command: "other-mcp"},
+	}}
+	path, err := claude.Adapter{}.Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(fixture)
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := &App{Canon: &config.Canonical{
+		Servers:  []config.Server{{Name: "github", Command: "gh-mcp", Enabled: true}},
+		Profiles: map[string]config.Profile{},
+	}}
+	if err := a.RenameServer("github", "gh", true); err != nil {
+		t.Fatalf("RenameServer() error = %v", err)
+	}
+
+	got, err := claude.Adapter{}.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.MCPServers["github"]; ok {
+		t.Error("old name is still in the Claude config")
+	}
+	if got.MCPServers["gh"].Command != "gh-mcp" {
+		t.Errorf("renamed entry missing: %+v", got.MCPServers)
+	}
+	if _, ok := got.MCPServers["unmanaged"]; !ok {
+		t.Error("unmanaged entry was dropped")
+	}
+}
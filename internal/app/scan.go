@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"mseep/internal/health"
+	"mseep/internal/inventory"
+	"mseep/internal/mcp"
+	"mseep/internal/scan"
+	"mseep/internal/style"
+)
+
+// ScanReport is the result of scanning enabled servers' tool definitions
+type ScanReport struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Servers   []string          `json:"servers"`
+	Errors    map[string]string `json:"errors,omitempty"`
+	Findings  []scan.Finding    `json:"findings"`
+}
+
+// Scan fetches tool definitions from enabled servers and flags suspicious
+// content. format is one of table, json or sarif. With cached set, stored
+// inventories are used instead of launching servers.
+func (a *App) Scan(serverFilter, format string, cached bool, timeout time.Duration) (string, error) {
+	switch format {
+	case "", "table", "json", "sarif":
+	default:
+		return "", fmt.Errorf("unknown format %q (want table, json or sarif)", format)
+	}
+
+	report := ScanReport{Timestamp: time.Now(), Servers: []string{}, Errors: map[string]string{}}
+	mgr := health.NewManager()
+
+	var invs []*inventory.Inventory
+	for _, srv := range a.Canon.Servers {
+		if !srv.EnabledAnywhere() || (serverFilter != "" && !matchesFilter(srv, serverFilter)) {
+			continue
+		}
+
+		var inv *inventory.Inventory
+		var err error
+		if cached {
+			inv, err = inventory.Load(srv.Name)
+			if err == nil && inv == nil {
+				err = fmt.Errorf("no cached inventory")
+			}
+		} else {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			inv, err = inventory.Fetch(ctx, mgr, srv)
+			cancel()
+			if err == nil {
+				err = inventory.Save(inv)
+			}
+		}
+		if err != nil {
+			report.Errors[srv.Name] = err.Error()
+			continue
+		}
+
+		report.Servers = append(report.Servers, srv.Name)
+		invs = append(invs, inv)
+	}
+
+	if len(report.Servers) == 0 && len(report.Errors) == 0 {
+		return "", fmt.Errorf("no enabled servers found matching criteria")
+	}
+
+	report.Findings = scan.Scan(invs)
+	if report.Findings == nil {
+		report.Findings = []scan.Finding{}
+	}
+
+	switch format {
+	case "json":
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("error formatting json: %w", err)
+		}
+		return string(output) + "\n", nil
+	case "sarif":
+		output, err := scan.SARIF(report.Findings, mcp.ClientVersion)
+		if err != nil {
+			return "", fmt.Errorf("error formatting sarif: %w", err)
+		}
+		return string(output) + "\n", nil
+	}
+
+	return formatScanReport(report), nil
+}
+
+func formatScanReport(report ScanReport) string {
+	var output strings.Builder
+
+	output.WriteString(style.Title("Tool Security Scan"))
+	output.WriteString("\n")
+	output.WriteString(style.Muted(fmt.Sprintf("Scanned %d servers", len(report.Servers))) + "\n")
+
+	if len(report.Errors) > 0 {
+		output.WriteString(style.Header("Not Scanned") + "\n")
+		names := make([]string, 0, len(report.Errors))
+		for name := range report.Errors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			output.WriteString(style.Warning(fmt.Sprintf("%s: %s", name, report.Errors[name])) + "\n")
+		}
+	}
+
+	if len(report.Findings) == 0 {
+		output.WriteString("\n" + style.Success("No suspicious tool definitions found") + "\n")
+		return output.String()
+	}
+
+	output.WriteString(style.Header(fmt.Sprintf("Findings (%d)", len(report.Findings))))
+	var rows [][]string
+	for _, f := range report.Findings {
+		sev := string(f.Severity)
+		switch f.Severity {
+		case scan.SeverityHigh:
+			sev = "✗ " + sev
+		case scan.SeverityMedium:
+			sev = "⚠ " + sev
+		}
+		rows = append(rows, []string{sev, f.Server, f.Tool, f.Rule, oneLine(f.Message+evidenceSuffix(f.Evidence), 60)})
+	}
+	output.WriteString("\n" + style.StatusTable(rows, []string{"Severity", "Server", "Tool", "Rule", "Detail"}))
+	output.WriteString("\n" + style.Muted("Use '--format sarif' or '--format json' to export findings") + "\n")
+	return output.String()
+}
+
+func evidenceSuffix(evidence string) string {
+	if evidence == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%q)", evidence)
+}
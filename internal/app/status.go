@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"mseep/internal/adapters/claude"
 	"mseep/internal/adapters/cline"
@@ -32,6 +33,7 @@ type ServerStatus struct {
 	InSync          bool   `json:"in_sync"`
 	Tags            []string `json:"tags,omitempty"`
 	Transport       string `json:"transport,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"` // end of an enable --for in this client
 }
 
 func (a *App) Status(client string, jsonOutput bool) (string, error) {
@@ -69,6 +71,10 @@ func (a *App) Status(client string, jsonOutput bool) (string, error) {
 	output.WriteString(style.Title("mseep Status Report"))
 	output.WriteString("\n")
 
+	if client == "" {
+		output.WriteString(a.formatStatusMatrix(report))
+	}
+
 	for i, clientStatus := range report.Clients {
 		if i > 0 {
 			output.WriteString("\n")
@@ -109,6 +115,9 @@ func (a *App) Status(client string, jsonOutput bool) (string, error) {
 			canonStatus := "✗ Disabled"
 			if srv.EnabledCanon {
 				canonStatus = "✓ Enabled"
+				if srv.ExpiresAt != nil {
+					canonStatus += " until " + srv.ExpiresAt.Local().Format("Mon 15:04")
+				}
 			}
 			
 			clientStatus := "✗ Disabled"
@@ -281,12 +290,16 @@ func (a *App) getClientStatusByName(name string) (ClientStatus, error) {
 	for _, srv := range a.Canon.Servers {
 		serverMap[srv.Name] = &ServerStatus{
 			Name:          srv.Name,
-			EnabledCanon:  srv.Enabled,
+			EnabledCanon:  srv.EnabledIn(name),
 			EnabledClient: false,
 			InSync:        false,
 			Tags:          srv.Tags,
 			Transport:     srv.Transport,
 		}
+		if srv.Expires != nil && (srv.Expires.Client == "" || srv.Expires.Client == name) {
+			at := srv.Expires.At
+			serverMap[srv.Name].ExpiresAt = &at
+		}
 	}
 
 	// Then check which ones are in client config
@@ -318,4 +331,58 @@ func (a *App) getClientStatusByName(name string) (ClientStatus, error) {
 	})
 
 	return clientStatus, nil
-}
\ No newline at end of file
+}
+// formatStatusMatrix shows each server's canonical state per installed
+// client, marking cells where the client's config differs
+func (a *App) formatStatusMatrix(report StatusReport) string {
+	var installed []ClientStatus
+	for _, c := range report.Clients {
+		if c.Installed {
+			installed = append(installed, c)
+		}
+	}
+	if len(installed) == 0 || len(a.Canon.Servers) == 0 {
+		return ""
+	}
+
+	headers := []string{"Server"}
+	present := make([]map[string]bool, len(installed))
+	for i, c := range installed {
+		headers = append(headers, c.Name)
+		present[i] = map[string]bool{}
+		for _, srv := range c.Servers {
+			if srv.EnabledClient {
+				present[i][srv.Name] = true
+			}
+		}
+	}
+
+	var rows [][]string
+	differs := false
+	for _, srv := range a.Canon.Servers {
+		row := []string{srv.Name}
+		for i, c := range installed {
+			want := srv.EnabledIn(c.Name)
+			cell := "–"
+			if want {
+				cell = "✓"
+			}
+			if want != present[i][srv.Name] {
+				cell += "*"
+				differs = true
+			}
+			row = append(row, cell)
+		}
+		rows = append(rows, row)
+	}
+
+	var output strings.Builder
+	output.WriteString(style.Header("Client Matrix") + "\n")
+	output.WriteString(style.StatusTable(rows, headers))
+	output.WriteString(style.Muted("✓ enabled, – disabled"))
+	if differs {
+		output.WriteString(style.Muted("; * client config differs, run 'mseep apply'"))
+	}
+	output.WriteString("\n\n")
+	return output.String()
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"mseep/internal/config"
+	"mseep/internal/style"
+)
+
+// maxStash is how many profile applies can be undone
+const maxStash = 10
+
+// EnabledState is a server's enablement before a profile was applied
+type EnabledState struct {
+	Enabled      bool                 `json:"enabled"`
+	EnabledFor   map[string]bool      `json:"enabledFor,omitempty"`
+	AutoDisabled *config.AutoDisabled `json:"autoDisabled,omitempty"`
+}
+
+// StashEntry is the enabled set saved before applying a profile
+type StashEntry struct {
+	Profile string                  `json:"profile"`
+	Mode    string                  `json:"mode"`
+	At      time.Time               `json:"at"`
+	Servers map[string]EnabledState `json:"servers"`
+}
+
+func stashPath() (string, error) {
+	dir, err := config.EnsureDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profile-stash.json"), nil
+}
+
+func loadStash() ([]StashEntry, error) {
+	p, err := stashPath()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entries []StashEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("invalid profile stash %s: %w", p, err)
+	}
+	return entries, nil
+}
+
+func saveStash(entries []StashEntry) error {
+	p, err := stashPath()
+	if err != nil {
+		return err
+	}
+	if len(entries) > maxStash {
+		entries = entries[len(entries)-maxStash:]
+	}
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, b, 0o644)
+}
+
+// enabledStates returns every server's current enablement
+func (a *App) enabledStates() map[string]EnabledState {
+	states := map[string]EnabledState{}
+	for _, srv := range a.Canon.Servers {
+		state := EnabledState{Enabled: srv.Enabled, AutoDisabled: srv.AutoDisabled}
+		if len(srv.EnabledFor) > 0 {
+			state.EnabledFor = map[string]bool{}
+			for c, on := range srv.EnabledFor {
+				state.EnabledFor[c] = on
+			}
+		}
+		states[srv.Name] = state
+	}
+	return states
+}
+
+// restoreEnabled puts back the enablement in states. Servers added since
+// the states were taken are left as they are.
+func (a *App) restoreEnabled(states map[string]EnabledState) {
+	for i := range a.Canon.Servers {
+		srv := &a.Canon.Servers[i]
+		if state, ok := states[srv.Name]; ok {
+			srv.Enabled = state.Enabled
+			srv.EnabledFor = state.EnabledFor
+			srv.AutoDisabled = state.AutoDisabled
+		}
+	}
+}
+
+// stashEnabled records the enabled set from before profile was applied
+func stashEnabled(profile, mode string, states map[string]EnabledState) error {
+	entries, err := loadStash()
+	if err != nil {
+		return err
+	}
+	return saveStash(append(entries, StashEntry{Profile: profile, Mode: mode, At: time.Now(), Servers: states}))
+}
+
+// UndoProfile restores the enabled set from before the last profile apply
+// and pushes it to the detected clients
+func (a *App) UndoProfile(autoApprove bool) error {
+	entries, err := loadStash()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no profile apply to undo")
+	}
+	last := entries[len(entries)-1]
+
+	a.restoreEnabled(last.Servers)
+	if err := config.Save("", a.Canon); err != nil {
+		return fmt.Errorf("failed to save canonical config: %w", err)
+	}
+	if err := saveStash(entries[:len(entries)-1]); err != nil {
+		return fmt.Errorf("failed to update profile stash: %w", err)
+	}
+
+	fmt.Print(style.Success(fmt.Sprintf("Restored servers from before applying profile %q (%s)", last.Profile, last.At.Format("2006-01-02 15:04"))) + "\n")
+	if len(detectedClients()) == 0 {
+		return nil
+	}
+	return a.Apply("", "", autoApprove)
+}
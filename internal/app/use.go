@@ -0,0 +1,209 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mseep/internal/config"
+	"mseep/internal/project"
+	"mseep/internal/style"
+)
+
+// Use applies the servers named by the nearest .mseep.json at or above dir
+// to that project's client configs, restoring the configs of a project
+// applied earlier. With auto set, as from the shell hook, nothing is
+// printed unless something changed.
+func (a *App) Use(dir string, auto bool) (string, error) {
+	root, marker, err := project.Find(dir)
+	if err != nil {
+		return "", err
+	}
+	state, err := project.LoadState()
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+	if state != nil && state.Root != root {
+		if err := leaveProject(state); err != nil {
+			return "", err
+		}
+		output.WriteString(style.Muted("Restored project configs in "+state.Root) + "\n")
+		state = nil
+	}
+	if root == "" {
+		if output.Len() == 0 && !auto {
+			return style.Muted("No "+project.MarkerFile+" found in this directory or its parents") + "\n", nil
+		}
+		return output.String(), nil
+	}
+
+	servers, err := a.markerServers(marker)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filepath.Join(root, project.MarkerFile), err)
+	}
+	if state == nil {
+		state = &project.State{Root: root}
+	}
+	state.Servers = project.SortedNames(servers)
+
+	var written []string
+	for _, client := range marker.Clients {
+		path, err := project.ConfigPath(root, client)
+		if err != nil {
+			return "", err
+		}
+		existing, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		rendered, err := project.Render(client, existing, servers, a.Canon)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", path, err)
+		}
+		if bytes.Equal(existing, rendered) {
+			continue
+		}
+
+		if !hasBackup(state, path) {
+			backup, err := project.Backup(path)
+			if err != nil {
+				return "", err
+			}
+			state.Files = append(state.Files, backup)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(path, rendered, 0o644); err != nil {
+			return "", err
+		}
+		written = append(written, path)
+	}
+
+	if err := project.SaveState(state); err != nil {
+		return "", fmt.Errorf("failed to record project state: %w", err)
+	}
+
+	if len(written) == 0 {
+		if !auto {
+			output.WriteString(style.Success("Project configs already up to date for "+root) + "\n")
+		}
+		return output.String(), nil
+	}
+	what := fmt.Sprintf("%d servers", len(servers))
+	if marker.Profile != "" {
+		what = fmt.Sprintf("profile %q", marker.Profile)
+	}
+	output.WriteString(style.Success(fmt.Sprintf("Using %s for %s", what, root)) + "\n")
+	for _, p := range written {
+		output.WriteString(style.Muted("  wrote "+p) + "\n")
+	}
+	return output.String(), nil
+}
+
+// Unuse restores the configs of the applied project
+func (a *App) Unuse() (string, error) {
+	state, err := project.LoadState()
+	if err != nil {
+		return "", err
+	}
+	if state == nil {
+		return style.Muted("No project is active") + "\n", nil
+	}
+	if err := leaveProject(state); err != nil {
+		return "", err
+	}
+	return style.Success("Restored project configs in "+state.Root) + "\n", nil
+}
+
+// markerServers returns the canonical servers a marker names, through its
+// profile, its server list, or both
+func (a *App) markerServers(m *project.Marker) ([]config.Server, error) {
+	want := map[string]bool{}
+	if m.Profile != "" {
+		resolved, err := a.Canon.ResolveProfile(m.Profile)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range resolved.Names() {
+			want[name] = true
+		}
+	}
+	for _, name := range m.Servers {
+		if a.Canon.FindByName(name) == nil {
+			return nil, fmt.Errorf("server %q not found", name)
+		}
+		want[name] = true
+	}
+
+	var servers []config.Server
+	for _, srv := range a.Canon.Servers {
+		if want[srv.Name] && srv.AutoDisabled == nil {
+			servers = append(servers, srv)
+		}
+	}
+	return servers, nil
+}
+
+func leaveProject(state *project.State) error {
+	if err := state.Restore(); err != nil {
+		return fmt.Errorf("failed to restore project configs in %s: %w", state.Root, err)
+	}
+	return project.SaveState(nil)
+}
+
+func hasBackup(state *project.State, path string) bool {
+	for _, f := range state.Files {
+		if f.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Hook returns a script for shell that runs 'mseep use --auto' whenever
+// the working directory changes. Evaluate it from the shell's rc file.
+func Hook(shell string) (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "mseep"
+	}
+	q := "'" + strings.ReplaceAll(exe, "'", `'\''`) + "'"
+
+	switch shell {
+	case "zsh":
+		return fmt.Sprintf(`_mseep_hook() {
+  %s use --auto
+}
+typeset -ag chpwd_functions
+if (( ! ${chpwd_functions[(I)_mseep_hook]} )); then
+  chpwd_functions+=(_mseep_hook)
+fi
+_mseep_hook
+`, q), nil
+	case "bash":
+		return fmt.Sprintf(`_mseep_hook() {
+  if [ "$PWD" != "${_MSEEP_PWD:-}" ]; then
+    _MSEEP_PWD="$PWD"
+    %s use --auto
+  fi
+}
+case ";${PROMPT_COMMAND:-};" in
+  *";_mseep_hook;"*) ;;
+  *) PROMPT_COMMAND="_mseep_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac
+`, q), nil
+	case "fish":
+		return fmt.Sprintf(`function _mseep_hook --on-variable PWD
+    %s use --auto
+end
+_mseep_hook
+`, q), nil
+	}
+	return "", fmt.Errorf("unsupported shell %q (want zsh, bash or fish)", shell)
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"mseep/internal/config"
+	"mseep/internal/style"
+)
+
+// ValidateReport is the result of validating a canonical config file
+type ValidateReport struct {
+	File     string           `json:"file"`
+	Valid    bool             `json:"valid"`
+	Problems []config.Problem `json:"problems"`
+}
+
+// Validate checks a canonical config file, the default one when path is
+// empty, against the schema and semantic rules. Problems are listed as
+// file:line:column so editors and CI logs can jump to them. It does not
+// need a loadable config, so it works on the files it is meant to fix.
+func Validate(path string, jsonOutput bool) (string, bool, error) {
+	if path == "" {
+		var err error
+		path, err = config.DefaultPath()
+		if err != nil {
+			return "", false, err
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, err
+	}
+
+	report := ValidateReport{File: path, Problems: config.Validate(data)}
+	report.Valid = len(report.Problems) == 0
+	if jsonOutput {
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return "", false, fmt.Errorf("error formatting json: %w", err)
+		}
+		return string(output) + "\n", report.Valid, nil
+	}
+
+	if report.Valid {
+		return style.Success(path+" is valid") + "\n", true, nil
+	}
+	var output strings.Builder
+	for _, p := range report.Problems {
+		fmt.Fprintf(&output, "%s:%s\n", path, p)
+	}
+	noun := "problems"
+	if len(report.Problems) == 1 {
+		noun = "problem"
+	}
+	output.WriteString(style.Error(fmt.Sprintf("%d %s in %s", len(report.Problems), noun, path)) + "\n")
+	return output.String(), false, nil
+}
@@ -0,0 +1,219 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"mseep/internal/config"
+	"mseep/internal/health"
+	"mseep/internal/history"
+	"mseep/internal/style"
+)
+
+// maxTransitions is how many recent state changes the watch view keeps
+const maxTransitions = 10
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// Transition is a change in a server's status between two watch rounds
+type Transition struct {
+	Server  string             `json:"server"`
+	From    health.CheckStatus `json:"from"`
+	To      health.CheckStatus `json:"to"`
+	Message string             `json:"message"`
+	At      time.Time          `json:"at"`
+}
+
+// WatchOptions configures HealthWatch
+type WatchOptions struct {
+	Client   string
+	Server   string
+	Interval time.Duration
+	// Concurrency caps simultaneous checks; zero uses the default
+	Concurrency int
+	// Hook runs through the shell when a server goes from healthy to
+	// unhealthy; the terminal bell rings when it is empty
+	Hook string
+}
+
+// HealthWatch re-runs health checks every interval and redraws a live table
+// on w until ctx is cancelled. Results are recorded in the health history
+// and failure policies are enforced as in a single 'mseep health' run. The
+// canonical config is reloaded every round, so edits made while watching
+// are checked and are not overwritten by policy changes.
+func (a *App) HealthWatch(ctx context.Context, opts WatchOptions, w io.Writer) error {
+	if opts.Interval <= 0 {
+		return fmt.Errorf("watch interval must be positive")
+	}
+
+	manager, err := newHealthManager(opts.Client, opts.Concurrency)
+	if err != nil {
+		return err
+	}
+	store, err := history.Open()
+	if err != nil {
+		return err
+	}
+
+	previous := map[string]health.CheckStatus{}
+	var transitions []Transition
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.reload(); err != nil {
+			return err
+		}
+		servers := a.getServersForHealthCheck(opts.Client, opts.Server)
+		if len(servers) == 0 {
+			return fmt.Errorf("no servers found matching criteria")
+		}
+
+		results, _ := a.runChecks(ctx, manager, servers)
+		if ctx.Err() != nil {
+			// Interrupted mid-round; the partial results are not meaningful
+			return nil
+		}
+
+		if err := store.Append(results); err != nil {
+			return fmt.Errorf("failed to record health history: %w", err)
+		}
+		// Reload again since the checks may have taken a while
+		if err := a.reload(); err != nil {
+			return err
+		}
+		decisions, err := a.handleHealthFixes(store, results, nil, false)
+		if err != nil {
+			return fmt.Errorf("failed to apply health policy: %w", err)
+		}
+
+		changed := map[string]bool{}
+		for _, r := range results {
+			prev, seen := previous[r.ServerName]
+			previous[r.ServerName] = r.Status
+			if !seen || prev == r.Status {
+				continue
+			}
+			t := Transition{Server: r.ServerName, From: prev, To: r.Status, Message: r.Message, At: r.Timestamp}
+			transitions = append(transitions, t)
+			changed[r.ServerName] = true
+			if prev == health.StatusHealthy {
+				a.alert(ctx, opts.Hook, t, w)
+			}
+		}
+		if len(transitions) > maxTransitions {
+			transitions = transitions[len(transitions)-maxTransitions:]
+		}
+
+		report := HealthReport{
+			Timestamp: time.Now(),
+			Results:   results,
+			Summary:   createHealthSummary(results),
+			Policy:    decisions,
+		}
+		fmt.Fprint(w, clearScreen+formatWatchView(report, changed, transitions, opts.Interval))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// reload replaces the canonical config with the one on disk
+func (a *App) reload() error {
+	canon, err := config.Load("")
+	if err != nil {
+		return fmt.Errorf("failed to reload canonical config: %w", err)
+	}
+	a.Canon = canon
+	return nil
+}
+
+// alert rings the terminal bell or runs the hook command for a server that
+// stopped being healthy. The hook sees the transition in MSEEP_* variables.
+func (a *App) alert(ctx context.Context, hook string, t Transition, w io.Writer) {
+	if hook == "" {
+		fmt.Fprint(w, "\a")
+		return
+	}
+
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.CommandContext(ctx, shell, flag, hook)
+	cmd.Env = append(os.Environ(),
+		"MSEEP_SERVER="+t.Server,
+		"MSEEP_FROM="+string(t.From),
+		"MSEEP_STATUS="+string(t.To),
+		"MSEEP_MESSAGE="+t.Message,
+	)
+	cmd.Stdout = io.Discard
+	cmd.Stderr = io.Discard
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintln(os.Stderr, style.Warning(fmt.Sprintf("hook failed for %s: %v", t.Server, err)))
+		return
+	}
+	// Reap in the background so a slow hook does not delay the next round
+	go cmd.Wait()
+}
+
+func formatWatchView(report HealthReport, changed map[string]bool, transitions []Transition, interval time.Duration) string {
+	var output strings.Builder
+
+	output.WriteString(style.Title("Health Watch"))
+	output.WriteString("\n")
+	output.WriteString(style.Muted(fmt.Sprintf("Checking %d servers every %s · last run %s · Ctrl-C to stop",
+		report.Summary.Total, interval, report.Timestamp.Format("15:04:05"))) + "\n")
+
+	var rows [][]string
+	for _, result := range report.Results {
+		status := statusLabel(result.Status)
+		if changed[result.ServerName] {
+			// The style helpers add their own icon
+			if result.Status == health.StatusHealthy {
+				status = style.Success(string(result.Status) + " ↑")
+			} else {
+				status = style.Error(string(result.Status) + " ↓")
+			}
+		}
+		rows = append(rows, []string{
+			result.ServerName,
+			result.Type,
+			status,
+			result.Duration.Round(time.Millisecond).String(),
+			oneLine(result.Message, 50),
+		})
+	}
+	output.WriteString("\n")
+	output.WriteString(style.StatusTable(rows, []string{"Server", "Type", "Status", "Duration", "Message"}))
+
+	if len(transitions) > 0 {
+		output.WriteString("\n" + style.Header("Recent Transitions") + "\n")
+		for i := len(transitions) - 1; i >= 0; i-- {
+			t := transitions[i]
+			line := fmt.Sprintf("%s %s: %s → %s", t.At.Format("15:04:05"), t.Server, t.From, t.To)
+			if t.To == health.StatusHealthy {
+				output.WriteString(style.Success(line) + "\n")
+			} else {
+				output.WriteString(style.Error(line) + style.Muted(" "+oneLine(t.Message, 60)) + "\n")
+			}
+		}
+	}
+
+	for _, d := range report.Policy {
+		output.WriteString(style.Warning(fmt.Sprintf("Policy %s %s: %s", d.Action, d.Server, d.Reason)) + "\n")
+	}
+
+	return output.String()
+}
@@ -1,6 +1,10 @@
 package app
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"mseep/internal/adapters/claude"
 	"mseep/internal/adapters/cline"
 	"mseep/internal/adapters/cursor"
@@ -8,6 +12,8 @@ import (
 	"mseep/internal/adapters/warp"
 	"mseep/internal/config"
 	"mseep/internal/fuzzy"
+	"mseep/internal/launchenv"
+	"mseep/internal/style"
 )
 
 type App struct {
@@ -20,28 +26,65 @@ func LoadApp() (*App, error) {
 	return &App{Canon: c}, nil
 }
 
-// Basic fuzzy enable/disable/toggle for Claude only (MVP)
-func (a *App) Toggle(mode, query, client string, assumeYes bool) (string, error) {
-	// index
+// serverIndex builds the fuzzy index over canonical servers
+func (a *App) serverIndex() []fuzzy.Index {
 	idx := make([]fuzzy.Index, 0, len(a.Canon.Servers))
 	for _, s := range a.Canon.Servers {
 		idx = append(idx, fuzzy.Index{Name: s.Name, Aliases: s.Aliases, Tags: s.Tags})
 	}
-	bestMatch, err := fuzzy.SelectBest(query, idx, assumeYes)
+	return idx
+}
+
+// selectServer resolves a fuzzy query to a canonical server
+func (a *App) selectServer(query string, assumeYes bool) (*config.Server, error) {
+	bestMatch, err := fuzzy.SelectBest(query, a.serverIndex(), assumeYes)
+	if err != nil { return nil, err }
+	return a.Canon.FindByName(bestMatch.Name), nil
+}
+
+// Fuzzy enable/disable/toggle. With client set only that client's state
+// changes; otherwise the server changes for every client.
+func (a *App) Toggle(mode, query, client string, assumeYes bool) (string, error) {
+	return a.toggle(mode, query, client, assumeYes, 0)
+}
+
+// EnableFor enables a server for duration d; Reconcile disables it after
+func (a *App) EnableFor(query, client string, assumeYes bool, d time.Duration) (string, error) {
+	if d <= 0 {
+		return "", fmt.Errorf("duration must be positive, got %s", d)
+	}
+	return a.toggle("enable", query, client, assumeYes, d)
+}
+
+func (a *App) toggle(mode, query, client string, assumeYes bool, d time.Duration) (string, error) {
+	if client != "" && !knownClient(client) {
+		return "", fmt.Errorf("unknown client %q (want %s)", client, strings.Join(launchenv.Clients, ", "))
+	}
+	bestMatch, err := fuzzy.SelectBest(query, a.serverIndex(), assumeYes)
 	if err != nil { return "", err }
 	chosen := bestMatch.Name
 
 	// flip state in canonical
-	for i := range a.Canon.Servers {
-		if a.Canon.Servers[i].Name == chosen {
-			switch mode {
-			case "enable": a.Canon.Servers[i].Enabled = true
-			case "disable": a.Canon.Servers[i].Enabled = false
-			case "toggle": a.Canon.Servers[i].Enabled = !a.Canon.Servers[i].Enabled
-			}
-			break
+	srv := a.Canon.FindByName(chosen)
+	switch mode {
+	case "enable": srv.SetEnabled(client, true)
+	case "disable": srv.SetEnabled(client, false)
+	case "toggle":
+		if client == "" {
+			srv.SetEnabled("", !srv.EnabledAnywhere())
+		} else {
+			srv.SetEnabled(client, !srv.EnabledIn(client))
 		}
 	}
+	// A plain enable or disable ends a temporary enable in the same scope
+	var notice string
+	if d > 0 {
+		srv.Expires = &config.Expiry{At: time.Now().Add(d).Truncate(time.Second), Client: client}
+		notice = style.Success(fmt.Sprintf("%s enabled until %s", chosen, srv.Expires.At.Local().Format("Mon 15:04"))) + "\n" +
+			style.Muted("Run 'mseep reconcile' then, or from cron or a systemd timer, to disable it") + "\n"
+	} else if srv.Expires != nil && (client == "" || srv.Expires.Client == client) {
+		srv.Expires = nil
+	}
 	if err := config.Save("", a.Canon); err != nil { return "", err }
 
 	// apply to detected clients or specific client
@@ -80,5 +123,15 @@ func (a *App) Toggle(mode, query, client string, assumeYes bool) (string, error)
 		}
 	}
 	
-	return diff, lastErr
+	return notice + diff, lastErr
+}
+
+// knownClient reports whether name is a client mseep can configure
+func knownClient(name string) bool {
+	for _, c := range launchenv.Clients {
+		if c == name {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,427 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"mseep/internal/config"
+)
+
+// Kind identifies a profile bundle file
+const Kind = "mseep-profile-bundle"
+
+// Version is the bundle format version written by Export
+const Version = 1
+
+// Placeholder replaces secret values in exported server definitions
+const Placeholder = "<secret>"
+
+// Bundle is a profile with everything needed to recreate it elsewhere:
+// the profiles it extends and the definitions of its servers
+type Bundle struct {
+	Kind       string                    `json:"kind"`
+	Version    int                       `json:"version"`
+	Profile    string                    `json:"profile"`
+	Profiles   map[string]config.Profile `json:"profiles"`
+	Servers    []config.Server           `json:"servers"`
+	Secrets    []Secret                  `json:"secrets,omitempty"`
+	ExportedAt time.Time                 `json:"exportedAt"`
+}
+
+// Secret is a value that was replaced by Placeholder on export
+type Secret struct {
+	Server string `json:"server"`
+	// Field is env, header, bearerToken, arg (a flag value in Args),
+	// urlPassword or urlQuery (parts of a URL in Args or the health URL)
+	Field string `json:"field"`
+	// Key is the variable, header, flag or query parameter name; empty for
+	// bearerToken and urlPassword
+	Key string `json:"key,omitempty"`
+}
+
+func (s Secret) String() string {
+	if s.Key == "" {
+		return fmt.Sprintf("%s %s", s.Server, s.Field)
+	}
+	return fmt.Sprintf("%s %s %s", s.Server, s.Field, s.Key)
+}
+
+// Export bundles profile name, the profiles it extends and the servers it
+// resolves to. Secret values are replaced by Placeholder; secret
+// references (env:, file:) are kept as they are.
+func Export(canon *config.Canonical, name string) (*Bundle, error) {
+	resolved, err := canon.ResolveProfile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{
+		Kind:       Kind,
+		Version:    Version,
+		Profile:    name,
+		Profiles:   map[string]config.Profile{},
+		Servers:    []config.Server{},
+		ExportedAt: time.Now().UTC(),
+	}
+	var collect func(string)
+	collect = func(p string) {
+		if _, done := b.Profiles[p]; done {
+			return
+		}
+		prof := canon.Profiles[p]
+		b.Profiles[p] = prof
+		for _, parent := range prof.Extends {
+			collect(parent)
+		}
+	}
+	collect(name)
+
+	for _, member := range resolved.Names() {
+		srv := *canon.FindByName(member)
+		srv.Enabled = false
+		srv.EnabledFor = nil
+		srv.AutoDisabled = nil
+		srv.Expires = nil
+		srv, secrets := mask(srv)
+		b.Servers = append(b.Servers, srv)
+		b.Secrets = append(b.Secrets, secrets...)
+	}
+	return b, nil
+}
+
+// Parse reads a bundle and checks its kind and version
+func Parse(data []byte) (*Bundle, error) {
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	if b.Kind != Kind {
+		return nil, fmt.Errorf("not a profile bundle (kind %q)", b.Kind)
+	}
+	if b.Version > Version {
+		return nil, fmt.Errorf("bundle version %d is newer than this mseep supports (%d)", b.Version, Version)
+	}
+	if _, ok := b.Profiles[b.Profile]; !ok {
+		return nil, fmt.Errorf("bundle does not define its profile %q", b.Profile)
+	}
+	return &b, nil
+}
+
+// mask copies srv with secret values replaced by Placeholder
+func mask(srv config.Server) (config.Server, []Secret) {
+	var secrets []Secret
+	if len(srv.Args) > 0 {
+		args := make([]string, len(srv.Args))
+		copy(args, srv.Args)
+		for i := 0; i < len(args); i++ {
+			if flag, ok := secretFlag(args, i); ok {
+				if eq := strings.Index(args[i], "="); eq >= 0 {
+					args[i] = args[i][:eq+1] + Placeholder
+				} else {
+					i++
+					args[i] = Placeholder
+				}
+				secrets = append(secrets, Secret{Server: srv.Name, Field: "arg", Key: flag})
+				continue
+			}
+			var found []Secret
+			args[i], found = maskURL(srv.Name, args[i])
+			secrets = append(secrets, found...)
+		}
+		srv.Args = args
+	}
+	if len(srv.Env) > 0 {
+		env := map[string]string{}
+		for k, v := range srv.Env {
+			if IsSecretName(k) && !isReference(v) {
+				v = Placeholder
+				secrets = append(secrets, Secret{Server: srv.Name, Field: "env", Key: k})
+			}
+			env[k] = v
+		}
+		srv.Env = env
+	}
+	if srv.Health != nil {
+		h := *srv.Health
+		if len(h.Headers) > 0 {
+			headers := map[string]string{}
+			for k, v := range h.Headers {
+				if IsSecretName(k) && !isReference(v) {
+					v = Placeholder
+					secrets = append(secrets, Secret{Server: srv.Name, Field: "header", Key: k})
+				}
+				headers[k] = v
+			}
+			h.Headers = headers
+		}
+		if h.BearerToken != "" && !isReference(h.BearerToken) {
+			h.BearerToken = Placeholder
+			secrets = append(secrets, Secret{Server: srv.Name, Field: "bearerToken"})
+		}
+		var found []Secret
+		h.URL, found = maskURL(srv.Name, h.URL)
+		secrets = append(secrets, found...)
+		srv.Health = &h
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].String() < secrets[j].String() })
+	return srv, secrets
+}
+
+// secretWords mark a variable or header name as holding a credential
+var secretWords = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "API_KEY", "APIKEY", "ACCESS_KEY", "PRIVATE_KEY", "AUTH", "CREDENTIAL", "COOKIE"}
+
+// IsSecretName reports whether an env var or header name looks like it
+// holds a credential
+func IsSecretName(name string) bool {
+	upper := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	for _, w := range secretWords {
+		if strings.Contains(upper, w) {
+			return true
+		}
+	}
+	return strings.HasSuffix(upper, "_KEY")
+}
+
+// secretFlag reports whether args[i] is a flag with a secret-looking name,
+// such as --api-key, that has a value either after = or in the next
+// argument, and returns the flag as written
+func secretFlag(args []string, i int) (string, bool) {
+	arg := args[i]
+	if !strings.HasPrefix(arg, "-") {
+		return "", false
+	}
+	flag, value, inline := strings.Cut(arg, "=")
+	if !inline {
+		if i+1 >= len(args) || strings.HasPrefix(args[i+1], "-") {
+			return "", false
+		}
+		value = args[i+1]
+	}
+	if !IsSecretName(strings.TrimLeft(flag, "-")) || value == "" || isReference(value) {
+		return "", false
+	}
+	return flag, true
+}
+
+// urlSpan is the position of a secret inside a URL
+type urlSpan struct {
+	field, key string
+	start, end int
+}
+
+// urlSpans finds the userinfo password and the secret-named query values
+// of the URL in s, which may also be a --flag=URL argument
+func urlSpans(s string) []urlSpan {
+	i := strings.Index(s, "://")
+	if i < 0 {
+		return nil
+	}
+	host := i + len("://")
+	hostEnd := len(s)
+	if j := strings.IndexAny(s[host:], "/?#"); j >= 0 {
+		hostEnd = host + j
+	}
+
+	var spans []urlSpan
+	if at := strings.LastIndex(s[host:hostEnd], "@"); at >= 0 {
+		if c := strings.Index(s[host:host+at], ":"); c >= 0 && c+1 < at {
+			spans = append(spans, urlSpan{field: "urlPassword", start: host + c + 1, end: host + at})
+		}
+	}
+	q := strings.Index(s[hostEnd:], "?")
+	if q < 0 {
+		return spans
+	}
+	start := hostEnd + q + 1
+	end := len(s)
+	if h := strings.Index(s[start:], "#"); h >= 0 {
+		end = start + h
+	}
+	for _, pair := range strings.Split(s[start:end], "&") {
+		if k, v, ok := strings.Cut(pair, "="); ok && v != "" {
+			if name, err := url.QueryUnescape(k); err == nil && isSecretParam(name) {
+				spans = append(spans, urlSpan{field: "urlQuery", key: k, start: start + len(k) + 1, end: start + len(pair)})
+			}
+		}
+		start += len(pair) + 1
+	}
+	return spans
+}
+
+// isSecretParam reports whether a query parameter looks like it carries
+// a credential
+func isSecretParam(name string) bool {
+	return IsSecretName(name) || strings.EqualFold(name, "key") || strings.EqualFold(name, "sig")
+}
+
+// maskURL replaces the password and secret query values of the URL in s
+// with Placeholder
+func maskURL(server, s string) (string, []Secret) {
+	spans := urlSpans(s)
+	var secrets []Secret
+	for i := len(spans) - 1; i >= 0; i-- {
+		sp := spans[i]
+		if isReference(s[sp.start:sp.end]) {
+			continue
+		}
+		s = s[:sp.start] + Placeholder + s[sp.end:]
+		secrets = append(secrets, Secret{Server: server, Field: sp.field, Key: sp.key})
+	}
+	return s, secrets
+}
+
+// urlFields returns the strings of srv that may hold URLs
+func urlFields(srv *config.Server) []*string {
+	var fields []*string
+	for i := range srv.Args {
+		fields = append(fields, &srv.Args[i])
+	}
+	if srv.Health != nil {
+		fields = append(fields, &srv.Health.URL)
+	}
+	return fields
+}
+
+// urlSecret finds s in the URLs of srv, preferring one still masked
+func (s Secret) urlSecret(srv *config.Server) (*string, urlSpan, bool) {
+	var first *string
+	var firstSpan urlSpan
+	for _, f := range urlFields(srv) {
+		for _, sp := range urlSpans(*f) {
+			if sp.field != s.Field || sp.key != s.Key {
+				continue
+			}
+			if (*f)[sp.start:sp.end] == Placeholder {
+				return f, sp, true
+			}
+			if first == nil {
+				first, firstSpan = f, sp
+			}
+		}
+	}
+	return first, firstSpan, first != nil
+}
+
+// flagValue returns the index of the value of flag in args and, for
+// --flag=value, the offset of the value within that argument
+func flagValue(args []string, flag string) (int, int, bool) {
+	for i, arg := range args {
+		if arg == flag && i+1 < len(args) {
+			return i + 1, 0, true
+		}
+		if strings.HasPrefix(arg, flag+"=") {
+			return i, len(flag) + 1, true
+		}
+	}
+	return 0, 0, false
+}
+
+// isReference reports whether v is a secret reference resolved at use
+func isReference(v string) bool {
+	return strings.HasPrefix(v, "env:") || strings.HasPrefix(v, "file:")
+}
+
+// Set fills in a secret of srv
+func (s Secret) Set(srv *config.Server, value string) {
+	switch s.Field {
+	case "env":
+		if srv.Env == nil {
+			srv.Env = map[string]string{}
+		}
+		srv.Env[s.Key] = value
+	case "header":
+		if srv.Health != nil {
+			if srv.Health.Headers == nil {
+				srv.Health.Headers = map[string]string{}
+			}
+			srv.Health.Headers[s.Key] = value
+		}
+	case "bearerToken":
+		if srv.Health != nil {
+			srv.Health.BearerToken = value
+		}
+	case "arg":
+		if i, off, ok := flagValue(srv.Args, s.Key); ok {
+			srv.Args[i] = srv.Args[i][:off] + value
+		}
+	case "urlPassword", "urlQuery":
+		if f, sp, ok := s.urlSecret(srv); ok {
+			if s.Field == "urlPassword" {
+				value = strings.TrimPrefix(url.UserPassword("", value).String(), ":")
+			} else {
+				value = url.QueryEscape(value)
+			}
+			*f = (*f)[:sp.start] + value + (*f)[sp.end:]
+		}
+	}
+}
+
+// Get returns the current value of a secret in srv
+func (s Secret) Get(srv config.Server) string {
+	switch s.Field {
+	case "env":
+		return srv.Env[s.Key]
+	case "header":
+		if srv.Health != nil {
+			return srv.Health.Headers[s.Key]
+		}
+	case "bearerToken":
+		if srv.Health != nil {
+			return srv.Health.BearerToken
+		}
+	case "arg":
+		if i, off, ok := flagValue(srv.Args, s.Key); ok {
+			return srv.Args[i][off:]
+		}
+	case "urlPassword", "urlQuery":
+		if f, sp, ok := s.urlSecret(&srv); ok {
+			v := (*f)[sp.start:sp.end]
+			if v == Placeholder {
+				return v
+			}
+			if s.Field == "urlQuery" {
+				if unescaped, err := url.QueryUnescape(v); err == nil {
+					return unescaped
+				}
+			} else if unescaped, err := url.PathUnescape(v); err == nil {
+				return unescaped
+			}
+			return v
+		}
+	}
+	return ""
+}
+
+// clear removes an unfilled secret so the placeholder is never used
+func (s Secret) clear(srv *config.Server) {
+	switch s.Field {
+	case "env":
+		delete(srv.Env, s.Key)
+	case "header":
+		if srv.Health != nil {
+			delete(srv.Health.Headers, s.Key)
+		}
+	case "bearerToken":
+		if srv.Health != nil {
+			srv.Health.BearerToken = ""
+		}
+	case "arg":
+		// Drop the flag too, since it cannot go without a value
+		if i, off, ok := flagValue(srv.Args, s.Key); ok {
+			if off == 0 {
+				i--
+				srv.Args = append(srv.Args[:i], srv.Args[i+2:]...)
+			} else {
+				srv.Args = append(srv.Args[:i], srv.Args[i+1:]...)
+			}
+		}
+	case "urlPassword", "urlQuery":
+		if f, sp, ok := s.urlSecret(srv); ok {
+			*f = (*f)[:sp.start] + (*f)[sp.end:]
+		}
+	}
+}
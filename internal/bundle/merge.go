@@ -0,0 +1,257 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"mseep/internal/config"
+)
+
+// Action resolves a name collision on import
+type Action string
+
+const (
+	ActionRename  Action = "rename"
+	ActionReplace Action = "replace"
+	ActionSkip    Action = "skip"
+)
+
+// Resolver makes the decisions an import needs from the user
+type Resolver interface {
+	// ServerConflict decides what to do with an incoming server whose name
+	// is taken by a different definition. For ActionRename it returns the
+	// new name, which suggested is a free default for.
+	ServerConflict(existing, incoming config.Server, suggested string) (Action, string, error)
+	// ProfileConflict does the same for a profile name
+	ProfileConflict(name, suggested string) (Action, string, error)
+	// Secret returns the value for a secret the bundle left out; empty
+	// leaves it unset
+	Secret(s Secret) (string, error)
+}
+
+// Result summarizes an import
+type Result struct {
+	Profile  string            `json:"profile"`
+	Added    []string          `json:"added"`
+	Replaced []string          `json:"replaced,omitempty"`
+	Kept     []string          `json:"kept,omitempty"`
+	Skipped  []string          `json:"skipped,omitempty"`
+	Renamed  map[string]string `json:"renamed,omitempty"`
+	Profiles []string          `json:"profiles"`
+	Unset    []Secret          `json:"unset,omitempty"`
+}
+
+// Merge adds the bundle's servers and profiles to canon. A server whose
+// name exists with the same definition is kept, including its secrets;
+// other collisions are settled by r. Imported servers, including ones that
+// replace a local definition, start disabled so a changed command never
+// launches before the profile is applied.
+func Merge(canon *config.Canonical, b *Bundle, r Resolver) (*Result, error) {
+	res := &Result{Profile: b.Profile, Added: []string{}, Profiles: []string{}, Renamed: map[string]string{}}
+	serverNames := map[string]string{}
+
+	secretsOf := map[string][]Secret{}
+	for _, s := range b.Secrets {
+		secretsOf[s.Server] = append(secretsOf[s.Server], s)
+	}
+
+	var added []config.Server
+	for _, incoming := range b.Servers {
+		// Secrets are filled into the copy, never into b
+		incoming := clone(incoming)
+		incoming.Enabled, incoming.EnabledFor, incoming.AutoDisabled, incoming.Expires = false, nil, nil, nil
+		name := incoming.Name
+		if existing := canon.FindByName(name); existing != nil {
+			if sameDefinition(*existing, incoming) {
+				res.Kept = append(res.Kept, name)
+				serverNames[name] = name
+				continue
+			}
+			action, newName, err := r.ServerConflict(*existing, incoming, freeServerName(canon, added, name, b.Profile))
+			if err != nil {
+				return nil, err
+			}
+			switch action {
+			case ActionSkip:
+				res.Skipped = append(res.Skipped, name)
+				serverNames[name] = name
+				continue
+			case ActionReplace:
+				if err := fillSecrets(&incoming, secretsOf[name], existing, r, res); err != nil {
+					return nil, err
+				}
+				*existing = incoming
+				res.Replaced = append(res.Replaced, name)
+				serverNames[name] = name
+				continue
+			case ActionRename:
+				if newName == "" || canon.FindByName(newName) != nil || hasServer(added, newName) {
+					return nil, fmt.Errorf("cannot rename %q to %q: name is empty or taken", name, newName)
+				}
+				res.Renamed[name] = newName
+				incoming.Name = newName
+			default:
+				return nil, fmt.Errorf("unknown action %q", action)
+			}
+		}
+		if err := fillSecrets(&incoming, secretsOf[name], nil, r, res); err != nil {
+			return nil, err
+		}
+		added = append(added, incoming)
+		res.Added = append(res.Added, incoming.Name)
+		serverNames[name] = incoming.Name
+	}
+	canon.Servers = append(canon.Servers, added...)
+
+	// Profiles are imported after servers so references follow renames
+	profileNames := map[string]string{}
+	names := make([]string, 0, len(b.Profiles))
+	for n := range b.Profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	incoming := map[string]config.Profile{}
+	for _, n := range names {
+		target := n
+		p := b.Profiles[n]
+		p.Include = rename(p.Include, serverNames)
+		p.Exclude = rename(p.Exclude, serverNames)
+		existing, exists := canon.Profiles[n]
+		if exists && sameProfile(existing, p) {
+			profileNames[n] = n
+			res.Profiles = append(res.Profiles, n)
+			continue
+		}
+		if exists {
+			action, newName, err := r.ProfileConflict(n, freeProfileName(canon, n))
+			if err != nil {
+				return nil, err
+			}
+			switch action {
+			case ActionSkip:
+				profileNames[n] = n
+				continue
+			case ActionReplace:
+			case ActionRename:
+				if _, taken := canon.Profiles[newName]; newName == "" || taken {
+					return nil, fmt.Errorf("cannot rename profile %q to %q: name is empty or taken", n, newName)
+				}
+				target = newName
+			default:
+				return nil, fmt.Errorf("unknown action %q", action)
+			}
+		}
+		profileNames[n] = target
+		incoming[target] = p
+	}
+
+	if canon.Profiles == nil {
+		canon.Profiles = map[string]config.Profile{}
+	}
+	for target, p := range incoming {
+		p.Extends = rename(p.Extends, profileNames)
+		canon.Profiles[target] = p
+		res.Profiles = append(res.Profiles, target)
+	}
+	sort.Strings(res.Profiles)
+	res.Profile = profileNames[b.Profile]
+	if len(res.Renamed) == 0 {
+		res.Renamed = nil
+	}
+	return res, nil
+}
+
+// fillSecrets asks for each placeholder of srv, reusing the value from
+// existing when it has one
+func fillSecrets(srv *config.Server, secrets []Secret, existing *config.Server, r Resolver, res *Result) error {
+	for _, s := range secrets {
+		// The server may have been renamed on import
+		s.Server = srv.Name
+		if s.Get(*srv) != Placeholder {
+			continue
+		}
+		if existing != nil {
+			if v := s.Get(*existing); v != "" && v != Placeholder {
+				s.Set(srv, v)
+				continue
+			}
+		}
+		v, err := r.Secret(s)
+		if err != nil {
+			return err
+		}
+		if v == "" {
+			s.clear(srv)
+			res.Unset = append(res.Unset, s)
+			continue
+		}
+		s.Set(srv, v)
+	}
+	return nil
+}
+
+// sameDefinition compares servers ignoring enablement and masked secrets
+func sameDefinition(existing, incoming config.Server) bool {
+	existing.Enabled, existing.EnabledFor, existing.AutoDisabled, existing.Expires = false, nil, nil, nil
+	incoming.Enabled, incoming.EnabledFor, incoming.AutoDisabled, incoming.Expires = false, nil, nil, nil
+	masked, _ := mask(existing)
+	a, _ := json.Marshal(masked)
+	b, _ := json.Marshal(incoming)
+	return string(a) == string(b)
+}
+
+func clone(srv config.Server) config.Server {
+	var out config.Server
+	data, _ := json.Marshal(srv)
+	json.Unmarshal(data, &out)
+	return out
+}
+
+func sameProfile(a, b config.Profile) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return string(x) == string(y)
+}
+
+func freeServerName(canon *config.Canonical, added []config.Server, name, profile string) string {
+	candidate := name + "-" + profile
+	for i := 2; canon.FindByName(candidate) != nil || hasServer(added, candidate); i++ {
+		candidate = fmt.Sprintf("%s-%s-%d", name, profile, i)
+	}
+	return candidate
+}
+
+func freeProfileName(canon *config.Canonical, name string) string {
+	candidate := name + "-imported"
+	for i := 2; ; i++ {
+		if _, taken := canon.Profiles[candidate]; !taken {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s-imported-%d", name, i)
+	}
+}
+
+func hasServer(servers []config.Server, name string) bool {
+	for _, s := range servers {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// rename maps entries through names, leaving selectors and unknown names
+func rename(entries []string, names map[string]string) []string {
+	if entries == nil {
+		return nil
+	}
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		if n, ok := names[e]; ok {
+			e = n
+		}
+		out[i] = e
+	}
+	return out
+}
@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 )
 
 type Canonical struct {
+	Schema   string            `json:"$schema,omitempty"` // for editor completion, see SchemaFile
 	Servers  []Server          `json:"servers"`
-	Profiles map[string][]string `json:"profiles"` // profile -> enabled server names
+	Profiles map[string]Profile `json:"profiles"`
 	Meta     Meta              `json:"meta"`
 }
 
@@ -28,16 +30,41 @@ type Server struct {
 	Args      []string          `json:"args,omitempty"`
 	Env       map[string]string `json:"env,omitempty"`
 	Transport string            `json:"transport,omitempty"` // stdio|http|tcp
-	Enabled   bool              `json:"enabled"`
+	Enabled   bool              `json:"enabled"`                // default for clients without an enabledFor entry
+	EnabledFor map[string]bool  `json:"enabledFor,omitempty"`    // client -> enabled, overriding Enabled
 	Health    *HealthSpec       `json:"healthCheck,omitempty"`
 	Policy    *PolicySpec       `json:"policy,omitempty"`
+	AutoDisabled *AutoDisabled  `json:"autoDisabled,omitempty"` // set while disabled by policy
+	Expires   *Expiry           `json:"expires,omitempty"`      // set by enable --for; reconcile disables after it
 }
 
 type HealthSpec struct {
-	Type      string        `json:"type"`        // stdio|http|tcp
-	URL       string        `json:"url,omitempty"`
-	TimeoutMs int           `json:"timeoutMs,omitempty"`
-	Retries   int           `json:"retries,omitempty"`
+	Type        string            `json:"type"` // stdio|http|tcp|mcp-http|mcp-sse|exec, or a registered custom type
+	URL         string            `json:"url,omitempty"`
+	Command     string            `json:"command,omitempty"` // exec: script that receives the server as JSON on stdin
+	Args        []string          `json:"args,omitempty"`    // exec: script arguments
+	TimeoutMs   int               `json:"timeoutMs,omitempty"`        // overall budget across retries, default 5000
+	AttemptTimeoutMs int          `json:"attemptTimeoutMs,omitempty"` // per attempt, default timeoutMs
+	Retries     int               `json:"retries,omitempty"`
+	BackoffMs   int               `json:"backoffMs,omitempty"`        // first retry delay, doubled each retry; default 500
+	Headers     map[string]string `json:"headers,omitempty"`     // values may be secret references
+	BearerToken string            `json:"bearerToken,omitempty"` // secret reference, e.g. env:API_TOKEN
+	Probe       *ProbeSpec        `json:"probe,omitempty"`       // stdio|mcp-http|mcp-sse: tool call after the handshake
+}
+
+// ProbeSpec is a tool call made after a successful handshake to check that
+// the server actually works, e.g. that its backend credentials are valid.
+// With no assertion the call passes unless the tool reports an error.
+type ProbeSpec struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	// JSONPath selects a value from the result, such as
+	// $.content[0].text.user; strings holding JSON are descended into.
+	// The value must exist and not be null or false unless Equals is set.
+	JSONPath string      `json:"jsonPath,omitempty"`
+	Equals   interface{} `json:"equals,omitempty"`
+	// Regex must match the selected value, or the text content without JSONPath
+	Regex string `json:"regex,omitempty"`
 }
 
 type PolicySpec struct {
@@ -47,6 +74,18 @@ type PolicySpec struct {
 	CooldownHours    int  `json:"cooldownHours,omitempty"`    // default 24
 }
 
+// AutoDisabled records why and when the health policy disabled a server
+type AutoDisabled struct {
+	At     time.Time `json:"at"`
+	Reason string    `json:"reason"`
+}
+
+// Expiry ends a temporary enable made with 'mseep enable --for'
+type Expiry struct {
+	At     time.Time `json:"at"`
+	Client string    `json:"client,omitempty"` // empty for every client
+}
+
 func DefaultPath() (string, error) {
 	dir, err := os.UserConfigDir()
 	if err != nil { return "", err }
@@ -71,14 +110,25 @@ func Load(path string) (*Canonical, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			c := &Canonical{Meta: Meta{Version: "1", UpdatedAt: time.Now()}, Profiles: map[string][]string{}}
+			c := &Canonical{Meta: Meta{Version: strconv.Itoa(SchemaVersion), UpdatedAt: time.Now()}, Profiles: map[string]Profile{}}
 			return c, nil
 		}
 		return nil, err
 	}
-	var c Canonical
-	if err := json.Unmarshal(b, &c); err != nil { return nil, err }
-	return &c, nil
+	c, from, err := Upgrade(b)
+	if err != nil { return nil, fmt.Errorf("%s: %w", path, err) }
+	if from < SchemaVersion {
+		// Keep the original so a downgrade can go back to it
+		if _, err := backupBeforeMigration(path, b, from); err != nil { return nil, err }
+		if c.Schema == "" {
+			c.Schema = "./" + SchemaFile
+		}
+		if c.Schema == "./"+SchemaFile {
+			if err := WriteSchemaFile(filepath.Dir(path)); err != nil { return nil, err }
+		}
+		if err := Save(path, c); err != nil { return nil, fmt.Errorf("failed to write migrated %s: %w", path, err) }
+	}
+	return c, nil
 }
 
 func Save(path string, c *Canonical) error {
@@ -102,10 +152,65 @@ func (c *Canonical) FindByName(name string) *Server {
 	return nil
 }
 
+// EnabledIn reports whether the server should be configured in client.
+// A server disabled by policy is disabled everywhere.
+func (s Server) EnabledIn(client string) bool {
+	if s.AutoDisabled != nil {
+		return false
+	}
+	if on, ok := s.EnabledFor[client]; ok {
+		return on
+	}
+	return s.Enabled
+}
+
+// EnabledAnywhere reports whether the server is enabled for any client.
+// Like EnabledIn it is false while the policy has the server disabled.
+func (s Server) EnabledAnywhere() bool {
+	if s.AutoDisabled != nil {
+		return false
+	}
+	if s.Enabled {
+		return true
+	}
+	for _, on := range s.EnabledFor {
+		if on {
+			return true
+		}
+	}
+	return false
+}
+
+// SetEnabled changes the server's state for one client, or for every
+// client when client is empty, which also drops per-client overrides.
+// Enabling overrides a policy disable, which would otherwise keep the
+// server off everywhere. Enabling for one client keeps the server off in
+// the others by turning them off explicitly.
+func (s *Server) SetEnabled(client string, on bool) {
+	if on && s.AutoDisabled != nil {
+		s.AutoDisabled = nil
+		if client != "" {
+			s.Enabled = false
+			for c := range s.EnabledFor {
+				s.EnabledFor[c] = false
+			}
+		}
+	}
+	if client == "" {
+		s.Enabled = on
+		s.EnabledFor = nil
+		return
+	}
+	if s.EnabledFor == nil {
+		s.EnabledFor = map[string]bool{}
+	}
+	s.EnabledFor[client] = on
+}
+
 func (c *Canonical) EnabledSet() map[string]bool {
 	m := map[string]bool{}
 	for _, s := range c.Servers {
-		if s.Enabled { m[s.Name] = true }
+		if s.EnabledAnywhere() { m[s.Name] = true }
 	}
 	return m
 }
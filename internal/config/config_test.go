@@ -35,9 +35,9 @@ func TestCanonicalMarshalUnmarshal(t *testing.T) {
 				},
 			},
 		},
-		Profiles: map[string][]string{
-			"dev":  {"test-server", "debug-server"},
-			"prod": {"prod-server"},
+		Profiles: map[string]Profile{
+			"dev":  {Include: []string{"test-server", "debug-server"}},
+			"prod": {Extends: []string{"dev"}, Exclude: []string{"debug-server"}},
 		},
 		Meta: Meta{
 			Version:   "1.0.0",
@@ -115,8 +115,8 @@ func TestLoadSave(t *testing.T) {
 				Enabled: true,
 			},
 		},
-		Profiles: map[string][]string{
-			"test": {"test-server"},
+		Profiles: map[string]Profile{
+			"test": {Include: []string{"test-server"}},
 		},
 		Meta: Meta{
 			Version:   "1.0.0",
@@ -170,3 +170,70 @@ func TestLoadNonExistent(t *testing.T) {
 	}
 }
 
+func TestEnabledIn(t *testing.T) {
+	s := Server{Name: "burp", Enabled: true}
+	s.SetEnabled("cursor", false)
+	if !s.EnabledIn("claude") || s.EnabledIn("cursor") {
+		t.Errorf("cursor override not applied: %+v", s)
+	}
+
+	s.SetEnabled("", false)
+	s.SetEnabled("claude", true)
+	if !s.EnabledIn("claude") || s.EnabledIn("cursor") || !s.EnabledAnywhere() {
+		t.Errorf("global disable should drop overrides: %+v", s)
+	}
+
+	s.AutoDisabled = &AutoDisabled{At: time.Now()}
+	if s.EnabledIn("claude") {
+		t.Error("policy-disabled server should be disabled in every client")
+	}
+}
+
+func TestEnablePolicyDisabled(t *testing.T) {
+	s := Server{Name: "burp", Enabled: true, AutoDisabled: &AutoDisabled{At: time.Now(), Reason: "failing"}}
+	s.SetEnabled("", false)
+	if s.AutoDisabled == nil {
+		t.Error("disabling should keep the policy record")
+	}
+
+	s.SetEnabled("", true)
+	if s.AutoDisabled != nil || !s.EnabledIn("claude") {
+		t.Errorf("explicit enable should override the policy disable: %+v", s)
+	}
+
+	s.EnabledFor = map[string]bool{"vscode": true}
+	s.AutoDisabled = &AutoDisabled{At: time.Now()}
+	s.SetEnabled("cursor", true)
+	if s.AutoDisabled != nil || !s.EnabledIn("cursor") {
+		t.Errorf("enabling for one client should override the policy disable: %+v", s)
+	}
+	if s.EnabledIn("claude") || s.EnabledIn("vscode") {
+		t.Errorf("enabling for one client should leave the others off: %+v", s)
+	}
+}
+
+func TestPolicyDisableKeepsClientChoices(t *testing.T) {
+	s := Server{Name: "burp", EnabledFor: map[string]bool{"cursor": true}}
+	s.AutoDisabled = &AutoDisabled{At: time.Now(), Reason: "failing"}
+	if s.EnabledAnywhere() || s.EnabledIn("cursor") {
+		t.Errorf("policy-disabled server should be off everywhere: %+v", s)
+	}
+
+	s.AutoDisabled = nil
+	if !s.EnabledIn("cursor") || s.EnabledIn("claude") {
+		t.Errorf("per-client choices should return after re-enable: %+v", s)
+	}
+}
+
+func TestEnabledSetPerClient(t *testing.T) {
+	c := &Canonical{Servers: []Server{
+		{Name: "global", Enabled: true},
+		{Name: "cursor-only", EnabledFor: map[string]bool{"cursor": true}},
+		{Name: "off"},
+		{Name: "failing", Enabled: true, AutoDisabled: &AutoDisabled{At: time.Now()}},
+	}}
+	set := c.EnabledSet()
+	if !set["global"] || !set["cursor-only"] || set["off"] || set["failing"] || len(set) != 2 {
+		t.Errorf("unexpected enabled set: %v", set)
+	}
+}
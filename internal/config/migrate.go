@@ -0,0 +1,125 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the canonical config schema this mseep reads and writes.
+// Bump it together with a new entry in migrations whenever the file format
+// changes in a way older files need converting for.
+const SchemaVersion = 2
+
+// migrations[v] upgrades a raw version v document to version v+1. They work
+// on decoded JSON rather than the current types so each step keeps seeing
+// the format it was written for.
+var migrations = map[int]func(doc map[string]interface{}) error{
+	1: migrateProfileLists,
+}
+
+// Upgrade parses a canonical config, migrating it from an older schema. It
+// returns the version the data was written with and refuses data from a
+// newer mseep.
+func Upgrade(data []byte) (*Canonical, int, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, err
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+	from, err := docVersion(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from > SchemaVersion {
+		return nil, from, fmt.Errorf("canonical config is schema version %d but this mseep only understands up to %d; upgrade mseep", from, SchemaVersion)
+	}
+
+	if from < SchemaVersion {
+		for v := from; v < SchemaVersion; v++ {
+			migrate, ok := migrations[v]
+			if !ok {
+				return nil, from, fmt.Errorf("no migration from schema version %d", v)
+			}
+			if err := migrate(doc); err != nil {
+				return nil, from, fmt.Errorf("migrating schema version %d to %d: %w", v, v+1, err)
+			}
+		}
+		meta, _ := doc["meta"].(map[string]interface{})
+		if meta == nil {
+			meta = map[string]interface{}{}
+			doc["meta"] = meta
+		}
+		meta["version"] = strconv.Itoa(SchemaVersion)
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, from, err
+		}
+	}
+
+	var c Canonical
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, from, err
+	}
+	if c.Profiles == nil {
+		c.Profiles = map[string]Profile{}
+	}
+	return &c, from, nil
+}
+
+// docVersion reads meta.version; files from before versioning have none
+// and are version 1. Only the major part of a dotted version counts.
+func docVersion(doc map[string]interface{}) (int, error) {
+	meta, _ := doc["meta"].(map[string]interface{})
+	raw, _ := meta["version"].(string)
+	if raw == "" {
+		return 1, nil
+	}
+	major, _, _ := strings.Cut(raw, ".")
+	v, err := strconv.Atoi(major)
+	if err != nil || v < 1 {
+		return 0, fmt.Errorf("invalid canonical config version %q", raw)
+	}
+	return v, nil
+}
+
+// backupBeforeMigration copies the file at path aside before it is
+// rewritten in the current schema
+func backupBeforeMigration(path string, data []byte, from int) (string, error) {
+	bak := fmt.Sprintf("%s.v%d.bak.%s", path, from, time.Now().Format("20060102-150405"))
+	if err := os.WriteFile(bak, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to back up %s before migrating: %w", path, err)
+	}
+	return bak, nil
+}
+
+// migrateProfileLists converts version 1 profiles, plain lists of server
+// names, to profile objects
+func migrateProfileLists(doc map[string]interface{}) error {
+	profiles, ok := doc["profiles"].(map[string]interface{})
+	if !ok {
+		if doc["profiles"] != nil {
+			return fmt.Errorf("profiles is not an object")
+		}
+		doc["profiles"] = map[string]interface{}{}
+		return nil
+	}
+	for name, p := range profiles {
+		switch v := p.(type) {
+		case []interface{}:
+			if len(v) == 0 {
+				profiles[name] = map[string]interface{}{}
+			} else {
+				profiles[name] = map[string]interface{}{"include": v}
+			}
+		case map[string]interface{}:
+		default:
+			return fmt.Errorf("profile %q is neither a list nor an object", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// TestMigrationGolden upgrades each testdata/migrate/*.json and compares
+// the result with its .golden file. Run with -update after adding a
+// migration or a fixture.
+func TestMigrationGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "migrate", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no migration fixtures")
+	}
+	for _, in := range inputs {
+		t.Run(filepath.Base(in), func(t *testing.T) {
+			data, err := os.ReadFile(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, _, err := Upgrade(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.MarshalIndent(c, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := strings.TrimSuffix(in, ".json") + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("migrated %s differs from %s:\n%s", in, golden, got)
+			}
+		})
+	}
+}
+
+func TestUpgradeVersions(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		from int
+	}{
+		{`{}`, 1},
+		{`{"meta":{"version":"1.0.0"}}`, 1},
+		{`{"meta":{"version":"2"}}`, 2},
+	} {
+		_, from, err := Upgrade([]byte(tc.data))
+		if err != nil || from != tc.from {
+			t.Errorf("Upgrade(%s) = version %d, %v; want %d", tc.data, from, err, tc.from)
+		}
+	}
+
+	_, _, err := Upgrade([]byte(`{"meta":{"version":"99"}}`))
+	if err == nil || !strings.Contains(err.Error(), "upgrade mseep") {
+		t.Errorf("newer version error = %v", err)
+	}
+	if _, _, err := Upgrade([]byte(`{"meta":{"version":"two"}}`)); err == nil {
+		t.Error("invalid version should fail")
+	}
+}
+
+func TestLoadMigratesWithBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "canonical.json")
+	original := []byte(`{"servers":[{"name":"a","command":"a"}],"profiles":{"p":["a"]},"meta":{"version":"1"}}`)
+	if err := os.WriteFile(path, original, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Meta.Version != "2" || len(c.Profiles["p"].Include) != 1 {
+		t.Errorf("loaded %+v", c)
+	}
+
+	backups, _ := filepath.Glob(path + ".v1.bak.*")
+	if len(backups) != 1 {
+		t.Fatalf("backups = %v, want one", backups)
+	}
+	if b, _ := os.ReadFile(backups[0]); !bytes.Equal(b, original) {
+		t.Errorf("backup = %s, want the original file", b)
+	}
+	rewritten, _ := os.ReadFile(path)
+	if !bytes.Contains(rewritten, []byte(`"include"`)) || !bytes.Contains(rewritten, []byte(`"version": "2"`)) {
+		t.Errorf("file not rewritten in the current schema:\n%s", rewritten)
+	}
+	if !bytes.Contains(rewritten, []byte(`"$schema": "./`+SchemaFile+`"`)) {
+		t.Errorf("migrated file does not point at the schema:\n%s", rewritten)
+	}
+	if schema, err := os.ReadFile(filepath.Join(filepath.Dir(path), SchemaFile)); err != nil || !bytes.Equal(schema, Schema) {
+		t.Errorf("schema file not written next to the config: %v", err)
+	}
+
+	// Loading again finds nothing to migrate
+	if _, err := Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := filepath.Glob(path + ".v1.bak.*"); len(backups) != 1 {
+		t.Errorf("second load made another backup: %v", backups)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"meta":{"version":"3"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load should refuse a newer schema")
+	}
+}
@@ -0,0 +1,335 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Selector prefixes for include and exclude entries: "tag:security"
+// selects servers by tag and "query:db" servers whose name, alias or tag
+// contains the text
+const (
+	TagPrefix   = "tag:"
+	QueryPrefix = "query:"
+)
+
+// IsSelector reports whether a profile entry selects servers dynamically
+// rather than naming one
+func IsSelector(rule string) bool {
+	return strings.HasPrefix(rule, TagPrefix) || strings.HasPrefix(rule, QueryPrefix)
+}
+
+// Profile is a named set of servers, built from other profiles plus
+// includes minus excludes. Include and exclude entries are server names or
+// selectors. Selectors, Tags and Query are matched against the servers
+// when the profile is resolved, so new servers join matching profiles
+// without editing them.
+type Profile struct {
+	Extends []string `json:"extends,omitempty"`
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+	// Tags includes every server carrying any of these tags
+	Tags []string `json:"tags,omitempty"`
+	// Query includes every server whose name, alias or tag contains it
+	Query string `json:"query,omitempty"`
+	// Clients limits the profile to these clients; applying it leaves
+	// other clients' servers alone. Empty targets every client.
+	Clients []string `json:"clients,omitempty"`
+	// Schedule enables the profile's servers only inside its window;
+	// 'mseep reconcile' enables and disables them as it opens and closes
+	Schedule *Schedule `json:"schedule,omitempty"`
+}
+
+// includeRules returns the include entries with Tags and Query expanded
+// into selectors
+func (p Profile) includeRules() []string {
+	rules := append([]string{}, p.Include...)
+	for _, t := range p.Tags {
+		rules = append(rules, TagPrefix+t)
+	}
+	if p.Query != "" {
+		rules = append(rules, QueryPrefix+p.Query)
+	}
+	return rules
+}
+
+// UnmarshalJSON also accepts the original form of a profile, a plain list
+// of server names
+func (p *Profile) UnmarshalJSON(b []byte) error {
+	var names []string
+	if err := json.Unmarshal(b, &names); err == nil {
+		*p = Profile{Include: names}
+		return nil
+	}
+	type plain Profile
+	var v plain
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*p = Profile(v)
+	return nil
+}
+
+// ProfileMember is a server in a resolved profile and where it came from
+type ProfileMember struct {
+	Server string `json:"server"`
+	// Profile is the profile whose include added the server; it differs
+	// from the resolved profile when inherited through extends
+	Profile string `json:"profile"`
+	// Rule is the include entry that matched, a server name or selector
+	Rule string `json:"rule"`
+}
+
+// ResolvedProfile is the server set a profile expands to
+type ResolvedProfile struct {
+	Name    string          `json:"name"`
+	Members []ProfileMember `json:"members"`
+	// Excluded lists servers removed by an exclude, with the rule that did it
+	Excluded []ProfileMember `json:"excluded,omitempty"`
+	// Missing lists included server names with no server definition
+	Missing []string `json:"missing,omitempty"`
+}
+
+// Names returns the member server names in canonical order
+func (r *ResolvedProfile) Names() []string {
+	names := make([]string, len(r.Members))
+	for i, m := range r.Members {
+		names[i] = m.Server
+	}
+	return names
+}
+
+// Has reports whether server is a member
+func (r *ResolvedProfile) Has(server string) bool {
+	for _, m := range r.Members {
+		if m.Server == server {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveProfile expands a profile's extends, includes and excludes into
+// its server set. Members are listed in canonical server order. Unknown
+// parents and extends cycles are errors.
+func (c *Canonical) ResolveProfile(name string) (*ResolvedProfile, error) {
+	return c.resolveProfile(name, nil)
+}
+
+func (c *Canonical) resolveProfile(name string, stack []string) (*ResolvedProfile, error) {
+	for i, s := range stack {
+		if s == name {
+			return nil, fmt.Errorf("profile cycle: %s", strings.Join(append(stack[i:], name), " -> "))
+		}
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		if len(stack) > 0 {
+			return nil, fmt.Errorf("profile %q extends unknown profile %q", stack[len(stack)-1], name)
+		}
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+	stack = append(stack, name)
+
+	members := map[string]ProfileMember{}
+	excluded := map[string]ProfileMember{}
+	var missing []string
+	add := func(m ProfileMember) {
+		if _, ok := members[m.Server]; !ok {
+			members[m.Server] = m
+		}
+		delete(excluded, m.Server)
+	}
+
+	for _, parent := range p.Extends {
+		r, err := c.resolveProfile(parent, stack)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range r.Members {
+			add(m)
+		}
+		for _, m := range r.Excluded {
+			if _, ok := members[m.Server]; !ok {
+				excluded[m.Server] = m
+			}
+		}
+		missing = append(missing, r.Missing...)
+	}
+
+	for _, rule := range p.includeRules() {
+		matched := c.selectServers(rule)
+		if len(matched) == 0 && !IsSelector(rule) {
+			missing = append(missing, rule)
+		}
+		for _, s := range matched {
+			add(ProfileMember{Server: s, Profile: name, Rule: rule})
+		}
+	}
+
+	for _, rule := range p.Exclude {
+		for _, s := range c.selectServers(rule) {
+			if _, ok := members[s]; ok {
+				delete(members, s)
+				excluded[s] = ProfileMember{Server: s, Profile: name, Rule: rule}
+			}
+		}
+	}
+
+	r := &ResolvedProfile{Name: name, Members: []ProfileMember{}, Missing: dedupe(missing)}
+	for _, srv := range c.Servers {
+		if m, ok := members[srv.Name]; ok {
+			r.Members = append(r.Members, m)
+		}
+		if m, ok := excluded[srv.Name]; ok {
+			r.Excluded = append(r.Excluded, m)
+		}
+	}
+	return r, nil
+}
+
+// selectServers returns the servers a profile rule matches, in canonical
+// order: every server matching a selector, otherwise the server with that
+// name
+func (c *Canonical) selectServers(rule string) []string {
+	var out []string
+	if tag, ok := strings.CutPrefix(rule, TagPrefix); ok {
+		for _, srv := range c.Servers {
+			if srv.HasTag(tag) {
+				out = append(out, srv.Name)
+			}
+		}
+		return out
+	}
+	if query, ok := strings.CutPrefix(rule, QueryPrefix); ok {
+		for _, srv := range c.Servers {
+			if srv.Matches(query) {
+				out = append(out, srv.Name)
+			}
+		}
+		return out
+	}
+	if c.FindByName(rule) != nil {
+		out = append(out, rule)
+	}
+	return out
+}
+
+// HasTag reports whether the server carries tag, ignoring case
+func (s Server) HasTag(tag string) bool {
+	for _, t := range s.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether the server's name, an alias or a tag contains
+// query, ignoring case
+func (s Server) Matches(query string) bool {
+	query = strings.ToLower(query)
+	if strings.Contains(strings.ToLower(s.Name), query) {
+		return true
+	}
+	for _, alias := range s.Aliases {
+		if strings.Contains(strings.ToLower(alias), query) {
+			return true
+		}
+	}
+	for _, tag := range s.Tags {
+		if strings.Contains(strings.ToLower(tag), query) {
+			return true
+		}
+	}
+	return false
+}
+
+func dedupe(in []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// RenameServer renames a server and every include and exclude entry that
+// names it, returning the profiles that changed
+func (c *Canonical) RenameServer(old, new string) ([]string, error) {
+	srv := c.FindByName(old)
+	if srv == nil {
+		return nil, fmt.Errorf("server %q not found", old)
+	}
+	if new == "" || IsSelector(new) {
+		return nil, fmt.Errorf("invalid server name %q", new)
+	}
+	if c.FindByName(new) != nil {
+		return nil, fmt.Errorf("server %q already exists", new)
+	}
+	srv.Name = new
+
+	var changed []string
+	for name, p := range c.Profiles {
+		include, a := replaceEntry(p.Include, old, new)
+		exclude, b := replaceEntry(p.Exclude, old, new)
+		if !a && !b {
+			continue
+		}
+		p.Include, p.Exclude = include, exclude
+		c.Profiles[name] = p
+		changed = append(changed, name)
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// RenameProfile renames a profile and every extends entry that names it,
+// returning the profiles whose extends changed
+func (c *Canonical) RenameProfile(old, new string) ([]string, error) {
+	p, ok := c.Profiles[old]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found", old)
+	}
+	if new == "" {
+		return nil, fmt.Errorf("profile name cannot be empty")
+	}
+	if _, exists := c.Profiles[new]; exists {
+		return nil, fmt.Errorf("profile %q already exists", new)
+	}
+	delete(c.Profiles, old)
+	c.Profiles[new] = p
+
+	var changed []string
+	for name, p := range c.Profiles {
+		if extends, ok := replaceEntry(p.Extends, old, new); ok {
+			p.Extends = extends
+			c.Profiles[name] = p
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// replaceEntry returns a copy of entries with old replaced by new and
+// whether it was present
+func replaceEntry(entries []string, old, new string) ([]string, bool) {
+	found := false
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		if e == old {
+			e, found = new, true
+		}
+		out[i] = e
+	}
+	if !found {
+		return entries, false
+	}
+	return out, true
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProfileLegacyList(t *testing.T) {
+	var c Canonical
+	if err := json.Unmarshal([]byte(`{"profiles":{"old":["a","b"],"new":{"extends":["old"],"exclude":["b"]}}}`), &c); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Profiles["old"].Include; !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("legacy profile include = %v", got)
+	}
+	if got := c.Profiles["new"].Extends; !reflect.DeepEqual(got, []string{"old"}) {
+		t.Errorf("object profile extends = %v", got)
+	}
+}
+
+func TestResolveProfile(t *testing.T) {
+	c := &Canonical{
+		Servers: []Server{
+			{Name: "burp", Tags: []string{"security"}},
+			{Name: "nmap", Tags: []string{"security", "network"}},
+			{Name: "fetch", Tags: []string{"web"}},
+			{Name: "browser", Tags: []string{"web"}},
+		},
+		Profiles: map[string]Profile{
+			"security":     {Include: []string{"tag:security"}},
+			"security+web": {Extends: []string{"security"}, Include: []string{"tag:web", "gone"}, Exclude: []string{"browser"}},
+			"quiet":        {Extends: []string{"security+web"}, Exclude: []string{"tag:network"}},
+			"loop-a":       {Extends: []string{"loop-b"}},
+			"loop-b":       {Extends: []string{"loop-a"}},
+			"orphan":       {Extends: []string{"nope"}},
+		},
+	}
+
+	r, err := c.ResolveProfile("security+web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"burp", "nmap", "fetch"}) {
+		t.Errorf("members = %v", got)
+	}
+	if r.Members[0].Profile != "security" || r.Members[0].Rule != "tag:security" {
+		t.Errorf("burp source = %+v, want inherited from security", r.Members[0])
+	}
+	if len(r.Excluded) != 1 || r.Excluded[0].Server != "browser" {
+		t.Errorf("excluded = %+v", r.Excluded)
+	}
+	if !reflect.DeepEqual(r.Missing, []string{"gone"}) {
+		t.Errorf("missing = %v", r.Missing)
+	}
+
+	r, err = c.ResolveProfile("quiet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"burp", "fetch"}) {
+		t.Errorf("quiet members = %v", got)
+	}
+
+	if _, err := c.ResolveProfile("loop-a"); err == nil || !strings.Contains(err.Error(), "loop-a -> loop-b -> loop-a") {
+		t.Errorf("cycle error = %v", err)
+	}
+	if _, err := c.ResolveProfile("orphan"); err == nil || !strings.Contains(err.Error(), `unknown profile "nope"`) {
+		t.Errorf("unknown parent error = %v", err)
+	}
+}
+
+func TestResolveDynamicProfile(t *testing.T) {
+	c := &Canonical{
+		Servers: []Server{
+			{Name: "postgres", Aliases: []string{"pg"}},
+			{Name: "burp", Tags: []string{"Security"}},
+		},
+		Profiles: map[string]Profile{
+			"security": {Tags: []string{"security"}},
+			"data":     {Query: "DB", Include: []string{"query:pg"}},
+		},
+	}
+
+	r, _ := c.ResolveProfile("security")
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"burp"}) {
+		t.Errorf("security members = %v", got)
+	}
+
+	// Servers added later join without editing the profile
+	c.Servers = append(c.Servers, Server{Name: "nmap", Tags: []string{"security"}}, Server{Name: "mongodb"})
+	r, _ = c.ResolveProfile("security")
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"burp", "nmap"}) {
+		t.Errorf("security members after adding nmap = %v", got)
+	}
+	r, _ = c.ResolveProfile("data")
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"postgres", "mongodb"}) {
+		t.Errorf("data members = %v", got)
+	}
+	if r.Members[1].Rule != "query:DB" || len(r.Missing) != 0 {
+		t.Errorf("mongodb rule = %q, missing = %v", r.Members[1].Rule, r.Missing)
+	}
+}
+
+func TestRenameServer(t *testing.T) {
+	c := &Canonical{
+		Servers: []Server{{Name: "pg"}, {Name: "mysql"}},
+		Profiles: map[string]Profile{
+			"db":    {Include: []string{"pg", "mysql"}},
+			"no-pg": {Extends: []string{"db"}, Exclude: []string{"pg"}},
+			"other": {Include: []string{"mysql"}},
+		},
+	}
+	changed, err := c.RenameServer("pg", "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(changed, []string{"db", "no-pg"}) {
+		t.Errorf("changed profiles = %v", changed)
+	}
+	if c.FindByName("postgres") == nil || !reflect.DeepEqual(c.Profiles["db"].Include, []string{"postgres", "mysql"}) || !reflect.DeepEqual(c.Profiles["no-pg"].Exclude, []string{"postgres"}) {
+		t.Errorf("after rename: %+v %+v", c.Servers, c.Profiles)
+	}
+	for _, bad := range [][2]string{{"gone", "x"}, {"mysql", "postgres"}, {"mysql", "tag:db"}, {"mysql", ""}} {
+		if _, err := c.RenameServer(bad[0], bad[1]); err == nil {
+			t.Errorf("RenameServer(%q, %q) should fail", bad[0], bad[1])
+		}
+	}
+}
+
+func TestRenameProfile(t *testing.T) {
+	c := &Canonical{Profiles: map[string]Profile{
+		"base":  {Include: []string{"a"}},
+		"child": {Extends: []string{"base"}},
+	}}
+	changed, err := c.RenameProfile("base", "core")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(changed, []string{"child"}) || !reflect.DeepEqual(c.Profiles["child"].Extends, []string{"core"}) {
+		t.Errorf("changed = %v, child = %+v", changed, c.Profiles["child"])
+	}
+	if _, ok := c.Profiles["base"]; ok {
+		t.Error("old profile name still present")
+	}
+	if _, err := c.RenameProfile("core", "child"); err == nil {
+		t.Error("renaming onto an existing profile should fail")
+	}
+}
@@ -0,0 +1,243 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// weekdays maps schedule day names to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Schedule is a weekly window in local time, such as weekdays 09:00-18:00.
+// A window whose end is before its start runs past midnight and belongs to
+// the day it starts on.
+type Schedule struct {
+	// Days are mon..sun, or weekdays/weekends; empty means every day
+	Days []string `json:"days,omitempty"`
+	// Start and End are HH:MM; both empty means all day. End is exclusive.
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// ParseSchedule builds a schedule from day names and an HH:MM-HH:MM
+// window, either of which may be empty. It returns nil when both are.
+func ParseSchedule(days []string, window string) (*Schedule, error) {
+	if len(days) == 0 && window == "" {
+		return nil, nil
+	}
+	s := &Schedule{}
+	for _, d := range days {
+		s.Days = append(s.Days, strings.ToLower(d))
+	}
+	if window != "" {
+		start, end, ok := strings.Cut(window, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid schedule window %q (want HH:MM-HH:MM)", window)
+		}
+		s.Start, s.End = strings.TrimSpace(start), strings.TrimSpace(end)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate checks the day names and times
+func (s Schedule) Validate() error {
+	if _, err := s.days(); err != nil {
+		return err
+	}
+	if (s.Start == "") != (s.End == "") {
+		return fmt.Errorf("schedule needs both start and end, or neither")
+	}
+	if s.Start == "" {
+		return nil
+	}
+	start, err := parseClock(s.Start)
+	if err != nil {
+		return err
+	}
+	end, err := parseClock(s.End)
+	if err != nil {
+		return err
+	}
+	if start == end {
+		return fmt.Errorf("schedule start and end are both %s", s.Start)
+	}
+	return nil
+}
+
+// Active reports whether t falls inside the window. An invalid schedule
+// is never active.
+func (s Schedule) Active(t time.Time) bool {
+	days, err := s.days()
+	if err != nil {
+		return false
+	}
+	on := func(d time.Weekday) bool { return len(days) == 0 || days[d] }
+	if s.Start == "" && s.End == "" {
+		return on(t.Weekday())
+	}
+	start, err1 := parseClock(s.Start)
+	end, err2 := parseClock(s.End)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+
+	now := t.Hour()*60 + t.Minute()
+	if start < end {
+		return on(t.Weekday()) && now >= start && now < end
+	}
+	// Past midnight: the evening part on a scheduled day, or the morning
+	// part after one
+	yesterday := (t.Weekday() + 6) % 7
+	return (on(t.Weekday()) && now >= start) || (on(yesterday) && now < end)
+}
+
+func (s Schedule) String() string {
+	days := "every day"
+	if len(s.Days) > 0 {
+		days = strings.Join(s.Days, ",")
+	}
+	if s.Start == "" {
+		return days
+	}
+	return fmt.Sprintf("%s %s-%s", days, s.Start, s.End)
+}
+
+func (s Schedule) days() (map[time.Weekday]bool, error) {
+	out := map[time.Weekday]bool{}
+	for _, d := range s.Days {
+		switch d = strings.ToLower(d); d {
+		case "weekdays":
+			for wd := time.Monday; wd <= time.Friday; wd++ {
+				out[wd] = true
+			}
+		case "weekends":
+			out[time.Saturday], out[time.Sunday] = true, true
+		default:
+			wd, ok := weekdays[d]
+			if !ok {
+				return nil, fmt.Errorf("unknown schedule day %q (want mon..sun, weekdays or weekends)", d)
+			}
+			out[wd] = true
+		}
+	}
+	return out, nil
+}
+
+// parseClock returns minutes since midnight for HH:MM
+func parseClock(v string) (int, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schedule time %q (want HH:MM)", v)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Change is a server state change made by Reconcile
+type Change struct {
+	Server  string `json:"server"`
+	Client  string `json:"client,omitempty"`
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason"`
+}
+
+// Reconcile disables servers whose temporary enable has expired and
+// enables or disables the servers of scheduled profiles to match their
+// windows at now. A server in several scheduled profiles stays enabled
+// while any of their windows is open; one under an unexpired temporary
+// enable is left on, and one disabled by policy is left alone.
+func (c *Canonical) Reconcile(now time.Time) ([]Change, error) {
+	var changes []Change
+	for i := range c.Servers {
+		srv := &c.Servers[i]
+		if srv.Expires == nil || now.Before(srv.Expires.At) {
+			continue
+		}
+		client := srv.Expires.Client
+		srv.Expires = nil
+		// Disabled even while the policy has it off, so it stays off
+		// when the policy re-enables it
+		wasOn := srv.EnabledIn(client) || (client == "" && srv.EnabledAnywhere())
+		srv.SetEnabled(client, false)
+		if wasOn {
+			changes = append(changes, Change{Server: srv.Name, Client: client, Reason: "temporary enable expired"})
+		}
+	}
+
+	type target struct{ server, client string }
+	want := map[target]bool{}
+	reason := map[target]string{}
+	names := make([]string, 0, len(c.Profiles))
+	for name, p := range c.Profiles {
+		if p.Schedule != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := c.Profiles[name]
+		if err := p.Schedule.Validate(); err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+		resolved, err := c.ResolveProfile(name)
+		if err != nil {
+			return nil, err
+		}
+		active := p.Schedule.Active(now)
+		clients := p.Clients
+		if len(clients) == 0 {
+			clients = []string{""}
+		}
+		for _, server := range resolved.Names() {
+			for _, client := range clients {
+				t := target{server, client}
+				if active && !want[t] {
+					want[t] = true
+					reason[t] = fmt.Sprintf("profile %q schedule open (%s)", name, p.Schedule)
+				} else if _, seen := reason[t]; !seen {
+					want[t] = false
+					reason[t] = fmt.Sprintf("profile %q schedule closed (%s)", name, p.Schedule)
+				}
+			}
+		}
+	}
+
+	targets := make([]target, 0, len(want))
+	for t := range want {
+		targets = append(targets, t)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].server != targets[j].server {
+			return targets[i].server < targets[j].server
+		}
+		return targets[i].client < targets[j].client
+	})
+	for _, t := range targets {
+		srv := c.FindByName(t.server)
+		on := want[t]
+		if srv.AutoDisabled != nil || (!on && srv.Expires != nil) {
+			continue
+		}
+		current := srv.EnabledIn(t.client)
+		if t.client == "" {
+			// Per-client overrides count as out of step with a global window
+			current = srv.Enabled && len(srv.EnabledFor) == 0
+			if !on {
+				current = srv.EnabledAnywhere()
+			}
+		}
+		if current == on {
+			continue
+		}
+		srv.SetEnabled(t.client, on)
+		changes = append(changes, Change{Server: t.server, Client: t.client, Enabled: on, Reason: reason[t]})
+	}
+	return changes, nil
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+// at returns a time in the week of Monday 2026-03-02
+func at(day time.Weekday, clock string) time.Time {
+	t, _ := time.Parse("15:04", clock)
+	return time.Date(2026, 3, 1+int(day), t.Hour(), t.Minute(), 0, 0, time.Local)
+}
+
+func TestScheduleActive(t *testing.T) {
+	office := Schedule{Days: []string{"weekdays"}, Start: "09:00", End: "18:00"}
+	night := Schedule{Days: []string{"fri"}, Start: "22:00", End: "02:00"}
+	weekend := Schedule{Days: []string{"weekends"}}
+
+	cases := []struct {
+		s    Schedule
+		t    time.Time
+		want bool
+	}{
+		{office, at(time.Monday, "09:00"), true},
+		{office, at(time.Friday, "17:59"), true},
+		{office, at(time.Friday, "18:00"), false},
+		{office, at(time.Saturday, "12:00"), false},
+		{night, at(time.Friday, "23:30"), true},
+		{night, at(time.Saturday, "01:59"), true},
+		{night, at(time.Saturday, "02:00"), false},
+		{night, at(time.Friday, "01:00"), false},
+		{weekend, at(time.Sunday, "03:00"), true},
+		{weekend, at(time.Monday, "03:00"), false},
+	}
+	for _, c := range cases {
+		if got := c.s.Active(c.t); got != c.want {
+			t.Errorf("%s at %s = %v, want %v", c.s, c.t.Format("Mon 15:04"), got, c.want)
+		}
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	if s, err := ParseSchedule(nil, ""); s != nil || err != nil {
+		t.Errorf("empty = %v, %v; want nil", s, err)
+	}
+	s, err := ParseSchedule([]string{"Mon", "wed"}, "09:30-17:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Start != "09:30" || s.End != "17:00" || !s.Active(at(time.Wednesday, "10:00")) {
+		t.Errorf("parsed = %+v", s)
+	}
+	for _, bad := range []struct {
+		days   []string
+		window string
+	}{
+		{[]string{"someday"}, ""},
+		{nil, "09:00"},
+		{nil, "9am-5pm"},
+		{nil, "10:00-10:00"},
+	} {
+		if _, err := ParseSchedule(bad.days, bad.window); err == nil {
+			t.Errorf("ParseSchedule(%v, %q) should fail", bad.days, bad.window)
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	now := at(time.Monday, "12:00")
+	c := &Canonical{
+		Servers: []Server{
+			{Name: "prod-db", Enabled: true, Expires: &Expiry{At: now.Add(-time.Minute)}},
+			{Name: "staging-db", EnabledFor: map[string]bool{"cursor": true}, Expires: &Expiry{At: now.Add(-time.Minute), Client: "cursor"}},
+			{Name: "jira", Tags: []string{"work"}},
+			{Name: "games", Tags: []string{"fun"}, Enabled: true},
+			{Name: "shared", Tags: []string{"work", "fun"}},
+			{Name: "pager", Tags: []string{"fun"}, Enabled: true, Expires: &Expiry{At: now.Add(time.Hour)}},
+			{Name: "unscheduled", Enabled: true},
+		},
+		Profiles: map[string]Profile{
+			"work": {Tags: []string{"work"}, Schedule: &Schedule{Days: []string{"weekdays"}, Start: "09:00", End: "18:00"}},
+			"fun":  {Tags: []string{"fun"}, Schedule: &Schedule{Days: []string{"weekends"}}},
+		},
+	}
+
+	changes, err := c.Reconcile(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]bool{}
+	for _, ch := range changes {
+		got[ch.Server+"/"+ch.Client] = ch.Enabled
+	}
+	want := map[string]bool{
+		"prod-db/":          false,
+		"staging-db/cursor": false,
+		"jira/":             true,
+		"shared/":           true,
+		"games/":            false,
+	}
+	if len(got) != len(want) {
+		t.Errorf("changes = %+v", changes)
+	}
+	for k, v := range want {
+		if on, ok := got[k]; !ok || on != v {
+			t.Errorf("change %s = %v (present %v), want %v", k, on, ok, v)
+		}
+	}
+	if c.Servers[0].Expires != nil || c.Servers[0].Enabled {
+		t.Errorf("prod-db = %+v, want disabled without expiry", c.Servers[0])
+	}
+	if !c.FindByName("pager").Enabled {
+		t.Error("an unexpired temporary enable should outlast a closed schedule")
+	}
+
+	// A second pass has nothing left to do
+	if changes, _ := c.Reconcile(now); len(changes) != 0 {
+		t.Errorf("second pass changes = %+v", changes)
+	}
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema is the JSON Schema for canonical.json, printed by 'mseep schema'
+//
+//go:embed schema.json
+var Schema []byte
+
+// SchemaFile is written next to canonical.json and referenced from its
+// $schema key so editors can complete and check it offline
+const SchemaFile = "canonical.schema.json"
+
+// schemaValidator checks decoded JSON against the subset of JSON Schema
+// used by schema.json: $ref into $defs, anyOf, type, enum, properties,
+// additionalProperties, required, items, minLength, minimum and pattern
+type schemaValidator struct {
+	defs     map[string]interface{}
+	problems []Problem
+}
+
+func newSchemaValidator() (*schemaValidator, map[string]interface{}, error) {
+	var root map[string]interface{}
+	if err := json.Unmarshal(Schema, &root); err != nil {
+		return nil, nil, fmt.Errorf("invalid embedded schema: %w", err)
+	}
+	defs, _ := root["$defs"].(map[string]interface{})
+	return &schemaValidator{defs: defs}, root, nil
+}
+
+func (v *schemaValidator) report(path, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *schemaValidator) validate(value interface{}, schema map[string]interface{}, path string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		def, _ := v.defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+		if def == nil {
+			v.report(path, "schema reference %s not found", ref)
+			return
+		}
+		v.validate(value, def, path)
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		v.validateAnyOf(value, anyOf, path)
+	}
+
+	if types := schemaTypes(schema); len(types) > 0 && !matchesType(value, types) {
+		v.report(path, "must be %s, not %s", strings.Join(types, " or "), jsonType(value))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		var allowed []string
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(value) {
+				found = true
+			}
+			if s := fmt.Sprint(e); s != "" {
+				allowed = append(allowed, s)
+			}
+		}
+		if !found {
+			v.report(path, "%q is not one of %s", fmt.Sprint(value), strings.Join(allowed, ", "))
+		}
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		v.validateObject(val, schema, path)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range val {
+				v.validate(item, items, fmt.Sprintf("%s/%d", path, i))
+			}
+		}
+	case string:
+		if min, ok := schema["minLength"].(float64); ok && len(val) < int(min) {
+			v.report(path, "must not be empty")
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(val) {
+				v.report(path, "%q does not match %s", val, pattern)
+			}
+		}
+	case json.Number:
+		if min, ok := schema["minimum"].(float64); ok {
+			if f, err := val.Float64(); err == nil && f < min {
+				v.report(path, "must be at least %v", min)
+			}
+		}
+	}
+}
+
+func (v *schemaValidator) validateObject(obj map[string]interface{}, schema map[string]interface{}, path string) {
+	props, _ := schema["properties"].(map[string]interface{})
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			if _, present := obj[r.(string)]; !present {
+				v.report(path, "missing required property %q", r)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		child := path + "/" + escapePointer(k)
+		if p, ok := props[k].(map[string]interface{}); ok {
+			v.validate(obj[k], p, child)
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				v.report(child, "unknown property %q", k)
+			}
+		case map[string]interface{}:
+			v.validate(obj[k], extra, child)
+		}
+	}
+}
+
+// validateAnyOf reports the problems of the branch whose type fits the
+// value, which reads better than a list of every branch's failures
+func (v *schemaValidator) validateAnyOf(value interface{}, branches []interface{}, path string) {
+	var fitting [][]Problem
+	var types []string
+	for _, b := range branches {
+		branch, _ := b.(map[string]interface{})
+		sub := &schemaValidator{defs: v.defs}
+		sub.validate(value, branch, path)
+		if len(sub.problems) == 0 {
+			return
+		}
+		t := schemaTypes(branch)
+		types = append(types, t...)
+		if len(t) == 0 || matchesType(value, t) {
+			fitting = append(fitting, sub.problems)
+		}
+	}
+	if len(fitting) == 1 {
+		v.problems = append(v.problems, fitting[0]...)
+		return
+	}
+	v.report(path, "must be %s, not %s", strings.Join(types, " or "), jsonType(value))
+}
+
+func schemaTypes(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		out := make([]string, len(t))
+		for i, v := range t {
+			out[i], _ = v.(string)
+		}
+		return out
+	}
+	return nil
+}
+
+func matchesType(value interface{}, types []string) bool {
+	actual := jsonType(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// escapePointer escapes a key for use in a JSON pointer
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// WriteSchemaFile keeps SchemaFile in dir in step with Schema
+func WriteSchemaFile(dir string) error {
+	p := filepath.Join(dir, SchemaFile)
+	if existing, err := os.ReadFile(p); err == nil && bytes.Equal(existing, Schema) {
+		return nil
+	}
+	return os.WriteFile(p, Schema, 0o644)
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ResolveSecret expands a secret reference so tokens need not be stored in
+// canonical.json. Supported forms:
+//
+//	env:NAME   value of environment variable NAME
+//	file:PATH  contents of PATH, trimmed of surrounding whitespace
+//
+// Any other value is returned unchanged.
+func ResolveSecret(ref string) (string, error) {
+	switch {
+	case strings.HasPrefix(ref, "env:"):
+		name := strings.TrimPrefix(ref, "env:")
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("secret %s: environment variable %s is not set", ref, name)
+		}
+		return v, nil
+	case strings.HasPrefix(ref, "file:"):
+		path := strings.TrimPrefix(ref, "file:")
+		if strings.HasPrefix(path, "~/") {
+			if h, err := os.UserHomeDir(); err == nil {
+				path = h + path[1:]
+			}
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("secret %s: %w", ref, err)
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	return ref, nil
+}
@@ -0,0 +1,269 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Problem is a mistake found in a canonical config. Path is a JSON pointer
+// to the offending value; Line and Column locate it in the file.
+type Problem struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	if p.Path == "" {
+		return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", p.Line, p.Column, p.Path, p.Message)
+}
+
+// urlHealthTypes are the health check types that need a url
+var urlHealthTypes = map[string]bool{"http": true, "tcp": true, "mcp-http": true, "mcp-sse": true}
+
+// Validate checks a canonical config against Schema and the rules the
+// schema cannot express: unique server names and aliases, profile
+// references, health checks with what their type needs, and schedules.
+// Problems are sorted by position.
+func Validate(data []byte) []Problem {
+	positions, err := scanPositions(data)
+	if err != nil {
+		offset := 0
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			offset = int(syntax.Offset)
+		}
+		line, col := lineColumn(data, offset)
+		return []Problem{{Line: line, Column: col, Message: "invalid JSON: " + err.Error()}}
+	}
+
+	var problems []Problem
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		problems = append(problems, Problem{Message: "invalid JSON: " + err.Error()})
+	} else if v, root, err := newSchemaValidator(); err != nil {
+		problems = append(problems, Problem{Message: err.Error()})
+	} else {
+		v.validate(doc, root, "")
+		problems = append(problems, v.problems...)
+	}
+
+	// Semantic rules run on the typed config. A value of the wrong type,
+	// already reported by the schema, leaves that field empty rather
+	// than hiding every other problem.
+	c, _, err := Upgrade(data)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		c = &Canonical{}
+		json.Unmarshal(data, c)
+		err = nil
+	}
+	if err != nil {
+		if len(problems) == 0 {
+			problems = append(problems, Problem{Path: "/meta/version", Message: err.Error()})
+		}
+	} else {
+		schemaProblems := problems
+		for _, p := range c.semanticProblems() {
+			if !coveredBy(schemaProblems, p.Path) {
+				problems = append(problems, p)
+			}
+		}
+	}
+
+	for i := range problems {
+		problems[i].Line, problems[i].Column = lineColumn(data, lookupPosition(positions, problems[i].Path))
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return problems
+}
+
+func (c *Canonical) semanticProblems() []Problem {
+	var problems []Problem
+	add := func(path, format string, args ...interface{}) {
+		problems = append(problems, Problem{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Names and aliases share one namespace for fuzzy matching
+	owner := map[string]string{}
+	for i, srv := range c.Servers {
+		path := fmt.Sprintf("/servers/%d", i)
+		key := strings.ToLower(srv.Name)
+		if prev, ok := owner[key]; ok {
+			add(path+"/name", "server name %q is already used by %s", srv.Name, prev)
+		} else if srv.Name != "" {
+			owner[key] = fmt.Sprintf("server %q", srv.Name)
+		}
+	}
+	for i, srv := range c.Servers {
+		for j, alias := range srv.Aliases {
+			key := strings.ToLower(alias)
+			if prev, ok := owner[key]; ok && prev != fmt.Sprintf("server %q", srv.Name) {
+				add(fmt.Sprintf("/servers/%d/aliases/%d", i, j), "alias %q is already used by %s", alias, prev)
+				continue
+			}
+			owner[key] = fmt.Sprintf("server %q", srv.Name)
+		}
+		if IsSelector(srv.Name) {
+			add(fmt.Sprintf("/servers/%d/name", i), "server name %q looks like a profile selector", srv.Name)
+		}
+	}
+
+	for i, srv := range c.Servers {
+		h := srv.Health
+		if h == nil {
+			continue
+		}
+		path := fmt.Sprintf("/servers/%d/healthCheck", i)
+		if urlHealthTypes[h.Type] && h.URL == "" {
+			add(path, "%s health check needs a url", h.Type)
+		}
+		if h.Type == "exec" && h.Command == "" {
+			add(path, "exec health check needs a command")
+		}
+	}
+
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := c.Profiles[name]
+		path := "/profiles/" + escapePointer(name)
+		for field, entries := range map[string][]string{"include": p.Include, "exclude": p.Exclude} {
+			for j, e := range entries {
+				if !IsSelector(e) && c.FindByName(e) == nil {
+					add(fmt.Sprintf("%s/%s/%d", path, field, j), "profile %q references unknown server %q", name, e)
+				}
+			}
+		}
+		for j, parent := range p.Extends {
+			if _, ok := c.Profiles[parent]; !ok {
+				add(fmt.Sprintf("%s/extends/%d", path, j), "profile %q extends unknown profile %q", name, parent)
+			}
+		}
+		if p.Schedule != nil {
+			if err := p.Schedule.Validate(); err != nil {
+				add(path+"/schedule", "%s", err)
+			}
+		}
+		// Cycles; unknown parents are reported above
+		if _, err := c.ResolveProfile(name); err != nil && strings.HasPrefix(err.Error(), "profile cycle") {
+			add(path+"/extends", "%s", err)
+		}
+	}
+	return problems
+}
+
+// coveredBy reports whether a problem was already found at or below path
+func coveredBy(problems []Problem, path string) bool {
+	for _, p := range problems {
+		if p.Path == path || strings.HasPrefix(p.Path, path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// scanPositions maps the JSON pointer of every value in data to its byte
+// offset. Object members point at their key, array elements at the value.
+func scanPositions(data []byte) (map[string]int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	pos := map[string]int{}
+	var walk func(path string, start int) error
+	walk = func(path string, start int) error {
+		pos[path] = start
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				keyStart := skipSeparators(data, int(dec.InputOffset()))
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err := walk(path+"/"+escapePointer(key.(string)), keyStart); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if err := walk(fmt.Sprintf("%s/%d", path, i), skipSeparators(data, int(dec.InputOffset()))); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		}
+		return err
+	}
+	if err := walk("", skipSeparators(data, 0)); err != nil {
+		return nil, err
+	}
+	return pos, nil
+}
+
+// skipSeparators moves offset past whitespace, commas and colons to the
+// start of the next token
+func skipSeparators(data []byte, offset int) int {
+	for offset < len(data) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// lookupPosition returns the offset of path, or of its nearest ancestor
+// present in the file
+func lookupPosition(positions map[string]int, path string) int {
+	// Version 1 profiles are plain lists, without an include key
+	if strings.HasPrefix(path, "/profiles/") {
+		if off, ok := positions[strings.Replace(path, "/include/", "/", 1)]; ok {
+			return off
+		}
+	}
+	for {
+		if off, ok := positions[path]; ok {
+			return off
+		}
+		i := strings.LastIndex(path, "/")
+		if i < 0 {
+			return 0
+		}
+		path = path[:i]
+	}
+}
+
+// lineColumn converts a byte offset to a 1-based line and column
+func lineColumn(data []byte, offset int) (int, int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return line, utf8.RuneCount(before[lineStart:]) + 1
+}
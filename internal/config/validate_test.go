@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateProblems(t *testing.T) {
+	data := []byte(`{
+  "servers": [
+    {"name": "burp", "aliases": ["bs"], "command": "burp-mcp", "transport": "websocket"},
+    {"name": "Burp", "command": "x", "enabeld": true},
+    {"name": "fetch", "aliases": ["bs"], "command": "npx", "healthCheck": {"type": "mcp-http"}},
+    {"name": "probe", "command": "p", "enabled": "yes", "healthCheck": {"type": "exec"}}
+  ],
+  "profiles": {
+    "web": {"include": ["fetch", "gone"], "extends": ["nope"]},
+    "old": ["burp", "missing"],
+    "night": {"schedule": {"days": ["funday"]}}
+  }
+}`)
+	want := []string{
+		`3:64: /servers/0/transport: "websocket" is not one of stdio, http, tcp`,
+		`4:6: /servers/1/name: server name "Burp" is already used by server "burp"`,
+		`4:38: /servers/1/enabeld: unknown property "enabeld"`,
+		`5:35: /servers/2/aliases/0: alias "bs" is already used by server "burp"`,
+		`5:60: /servers/2/healthCheck: mcp-http health check needs a url`,
+		`6:39: /servers/3/enabled: must be boolean, not string`,
+		`6:57: /servers/3/healthCheck: exec health check needs a command`,
+		`9:34: /profiles/web/include/1: profile "web" references unknown server "gone"`,
+		`9:55: /profiles/web/extends/0: profile "web" extends unknown profile "nope"`,
+		`10:21: /profiles/old/include/1: profile "old" references unknown server "missing"`,
+		`11:37: /profiles/night/schedule/days/0: "funday" is not one of mon, tue, wed, thu, fri, sat, sun, weekdays, weekends`,
+	}
+	var got []string
+	for _, p := range Validate(data) {
+		got = append(got, p.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateSyntaxAndVersion(t *testing.T) {
+	problems := Validate([]byte("{\n  \"servers\": [\n    {\"name\": }\n  ]\n}"))
+	if len(problems) != 1 || problems[0].Line != 3 || !strings.Contains(problems[0].Message, "invalid JSON") {
+		t.Errorf("syntax error problems = %+v", problems)
+	}
+
+	problems = Validate([]byte(`{"meta": {"version": "99"}}`))
+	if len(problems) != 1 || problems[0].Path != "/meta/version" || !strings.Contains(problems[0].Message, "upgrade mseep") {
+		t.Errorf("newer version problems = %+v", problems)
+	}
+}
+
+// TestSavedConfigValidates checks that everything mseep writes passes its
+// own schema, and that Save writes nothing but the config itself
+func TestSavedConfigValidates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "canonical.json")
+	c := &Canonical{
+		Servers: []Server{{
+			Name: "full", Aliases: []string{"f"}, Tags: []string{"t"}, Command: "cmd", Args: []string{"a"},
+			Env: map[string]string{"K": "v"}, Transport: "stdio", Enabled: true, EnabledFor: map[string]bool{"cursor": false},
+			Health: &HealthSpec{Type: "mcp-http", URL: "http://localhost", Command: "c", Args: []string{"x"}, TimeoutMs: 1,
+				AttemptTimeoutMs: 1, Retries: 1, BackoffMs: 1, Headers: map[string]string{"H": "v"}, BearerToken: "env:T",
+				Probe: &ProbeSpec{Tool: "t", Arguments: map[string]interface{}{"a": 1}, JSONPath: "$.a", Equals: 1, Regex: "x"}},
+			Policy:       &PolicySpec{AutoDisable: true, FailureThreshold: 1, WindowHours: 1, CooldownHours: 1},
+			AutoDisabled: &AutoDisabled{At: time.Now(), Reason: "r"},
+			Expires:      &Expiry{At: time.Now(), Client: "cursor"},
+		}},
+		Profiles: map[string]Profile{
+			"p": {Extends: []string{"q"}, Include: []string{"full", "tag:t"}, Exclude: []string{"query:x"}, Tags: []string{"t"},
+				Query: "f", Clients: []string{"cursor"}, Schedule: &Schedule{Days: []string{"weekdays"}, Start: "09:00", End: "17:00"}},
+			"q": {},
+		},
+	}
+	if err := Save(path, c); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := Validate(data); len(problems) != 0 {
+		t.Errorf("saved config has problems: %+v", problems)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc["$schema"]; ok {
+		t.Errorf("$schema = %v, want it left unset", doc["$schema"])
+	}
+	if _, err := os.Stat(filepath.Join(dir, SchemaFile)); !os.IsNotExist(err) {
+		t.Errorf("Save wrote the schema file: %v", err)
+	}
+}
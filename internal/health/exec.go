@@ -0,0 +1,125 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"mseep/internal/config"
+	"mseep/internal/launchenv"
+)
+
+// ExecChecker runs a user-provided script to check a server. The script
+// receives the server definition as JSON on stdin. Exit status 0 means
+// healthy and anything else unhealthy. If stdout is a JSON object such as
+//
+//	{"status": "unhealthy", "message": "database unreachable"}
+//
+// its status and message take precedence; otherwise the first line of
+// stdout becomes the message.
+type ExecChecker struct {
+	Env *launchenv.Env
+}
+
+// execOutput is the optional JSON a check script writes to stdout
+type execOutput struct {
+	Status  CheckStatus `json:"status"`
+	Message string      `json:"message"`
+}
+
+func (c *ExecChecker) Check(ctx context.Context, server config.Server) CheckResult {
+	result := CheckResult{
+		ServerName: server.Name,
+		Type:       "exec",
+		Status:     StatusError,
+	}
+
+	spec := server.Health
+	if spec == nil || spec.Command == "" {
+		result.Message = "no command specified for exec health check"
+		return result
+	}
+
+	env := c.Env
+	if env == nil {
+		env, _ = launchenv.For("")
+	}
+	launch := env.ForServer(server.Env)
+	command := spec.Command
+	if strings.HasPrefix(command, "~/") {
+		if h, err := os.UserHomeDir(); err == nil {
+			command = h + command[1:]
+		}
+	}
+	path, err := launch.LookPath(command)
+	if err != nil {
+		result.Message = fmt.Sprintf("failed to start check script: %v", err)
+		return result
+	}
+
+	input, err := json.Marshal(server)
+	if err != nil {
+		result.Message = fmt.Sprintf("failed to encode server: %v", err)
+		return result
+	}
+
+	cmd := exec.CommandContext(ctx, path, spec.Args...)
+	cmd.Env = launch.Vars
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.WaitDelay = shutdownGrace
+	stdout, stderr := newTailBuffer(MaxOutputBytes), newTailBuffer(MaxOutputBytes)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	runErr := cmd.Run()
+	out := stdout.String()
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		result.Status = StatusTimeout
+		result.Message = "check script did not finish before timeout"
+	case runErr == nil:
+		result.Status = StatusHealthy
+		result.Message = "check script passed"
+	case errors.As(runErr, &exitErr):
+		result.Status = StatusUnhealthy
+		result.Message = fmt.Sprintf("check script failed: %v", runErr)
+	default:
+		result.Message = fmt.Sprintf("failed to run check script: %v", runErr)
+		return result
+	}
+
+	if result.Status != StatusTimeout {
+		var parsed execOutput
+		if err := json.Unmarshal([]byte(strings.TrimSpace(out)), &parsed); err == nil {
+			if knownStatus(parsed.Status) {
+				result.Status = parsed.Status
+			}
+			if parsed.Message != "" {
+				result.Message = parsed.Message
+			}
+		} else if line, _, _ := strings.Cut(strings.TrimSpace(out), "\n"); line != "" {
+			result.Message = line
+		}
+	}
+
+	if result.Status != StatusHealthy {
+		result.Stdout, result.Stderr = out, stderr.String()
+	}
+	return result
+}
+
+// knownStatus reports whether s is a status mseep understands
+func knownStatus(s CheckStatus) bool {
+	switch s {
+	case StatusHealthy, StatusUnhealthy, StatusTimeout, StatusError, StatusProtocolError:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package health
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"mseep/internal/config"
+)
+
+func TestExecChecker(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	dir := t.TempDir()
+	script := func(name, body string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		status  CheckStatus
+		message string
+	}{
+		{"pass", "cat >/dev/null\necho all good\n", StatusHealthy, "all good"},
+		{"fail", "echo down\nexit 1\n", StatusUnhealthy, "down"},
+		{"json", `echo '{"status":"protocol_error","message":"bad reply"}'` + "\n", StatusProtocolError, "bad reply"},
+		{"stdin", "grep -q '\"name\":\"svc\"' || exit 3\n", StatusHealthy, "check script passed"},
+	}
+	c := &ExecChecker{}
+	for _, tt := range tests {
+		server := config.Server{Name: "svc", Health: &config.HealthSpec{Type: "exec", Command: script(tt.name, tt.body)}}
+		r := c.Check(context.Background(), server)
+		if r.Status != tt.status || r.Message != tt.message {
+			t.Errorf("%s: got %s %q, want %s %q", tt.name, r.Status, r.Message, tt.status, tt.message)
+		}
+	}
+
+	r := c.Check(context.Background(), config.Server{Name: "svc", Health: &config.HealthSpec{Type: "exec", Command: filepath.Join(dir, "missing")}})
+	if r.Status != StatusError || !strings.Contains(r.Message, "missing") {
+		t.Errorf("missing script: got %s %q", r.Status, r.Message)
+	}
+}
+
+type stubChecker struct{}
+
+func (stubChecker) Check(ctx context.Context, server config.Server) CheckResult {
+	return CheckResult{ServerName: server.Name, Status: StatusHealthy, Message: "stub"}
+}
+
+func TestRegister(t *testing.T) {
+	Register("stub-test", stubChecker{})
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "stub-test")
+		registryMu.Unlock()
+	}()
+
+	server := config.Server{Name: "svc", Health: &config.HealthSpec{Type: "stub-test"}}
+	if r := NewManager().CheckServer(context.Background(), server); r.Message != "stub" {
+		t.Errorf("registered checker not used: %+v", r)
+	}
+
+	for _, typ := range []string{"stdio", "stub-test"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("registering %s again should panic", typ)
+				}
+			}()
+			Register(typ, stubChecker{})
+		}()
+	}
+}
@@ -0,0 +1,300 @@
+package health
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// Drift is a pinned server whose tool definitions changed since approval
+type Drift struct {
+	Server  string
+	Message string
+}
+
+// driftType names tool drift in the machine-readable reports
+const driftType = "tool_drift"
+
+// JUnit renders results as a JUnit XML report with one testcase per
+// server, plus a failed mseep.pin testcase per drifted server. Check
+// misconfigurations (StatusError) are reported as errors and every other
+// non-healthy status as a failure.
+func JUnit(results []CheckResult, drifts []Drift, timestamp time.Time) ([]byte, error) {
+	suite := junitSuite{
+		Name:      "mseep health",
+		Tests:     len(results) + len(drifts),
+		Timestamp: timestamp.UTC().Format(time.RFC3339),
+	}
+
+	var total time.Duration
+	for _, r := range results {
+		total += r.Duration
+		tc := junitCase{
+			Name:      r.ServerName,
+			Classname: "mseep.health." + r.Type,
+			Time:      seconds(r.Duration),
+		}
+		problem := &junitProblem{Message: r.Message, Type: string(r.Status), Text: r.Message}
+		switch r.Status {
+		case StatusHealthy:
+		case StatusError:
+			tc.Error = problem
+			suite.Errors++
+		default:
+			tc.Failure = problem
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	for _, d := range drifts {
+		suite.Cases = append(suite.Cases, junitCase{
+			Name:      d.Server,
+			Classname: "mseep.pin",
+			Time:      seconds(0),
+			Failure:   &junitProblem{Message: d.Message, Type: driftType, Text: d.Message},
+		})
+		suite.Failures++
+	}
+	suite.Time = seconds(total)
+
+	out, err := xml.MarshalIndent(junitSuites{Suites: []junitSuite{suite}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// SARIF 2.1.0 subset used to export failed checks for code scanning tools
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string            `json:"id"`
+	ShortDescription     sarifMessage      `json:"shortDescription"`
+	DefaultConfiguration sarifRuleDefaults `json:"defaultConfiguration"`
+}
+
+type sarifRuleDefaults struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifRules has one rule per failing status. A misconfigured check is a
+// warning since the server itself may be fine.
+var sarifRules = []struct {
+	Status      CheckStatus
+	Level       string
+	Description string
+}{
+	{StatusUnhealthy, "error", "Server failed its health check"},
+	{StatusTimeout, "error", "Server health check timed out"},
+	{StatusProtocolError, "error", "Server did not speak MCP correctly"},
+	{StatusError, "warning", "Server health check is misconfigured"},
+}
+
+// SARIF renders results as a SARIF 2.1.0 log with one result per server
+// that is not healthy and one per drifted server. Servers have no file
+// location, so each result carries a logical location named after the
+// server.
+func SARIF(results []CheckResult, drifts []Drift, version string) ([]byte, error) {
+	driver := sarifDriver{Name: "mseep", Version: version}
+	levels := map[CheckStatus]string{}
+	for _, r := range sarifRules {
+		levels[r.Status] = r.Level
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   "health/" + string(r.Status),
+			ShortDescription:     sarifMessage{Text: r.Description},
+			DefaultConfiguration: sarifRuleDefaults{Level: r.Level},
+		})
+	}
+	driver.Rules = append(driver.Rules, sarifRule{
+		ID:                   "pin/" + driftType,
+		ShortDescription:     sarifMessage{Text: "Server tool definitions changed since approval"},
+		DefaultConfiguration: sarifRuleDefaults{Level: "error"},
+	})
+
+	out := []sarifResult{}
+	for _, r := range results {
+		if r.Status == StatusHealthy {
+			continue
+		}
+		level, ok := levels[r.Status]
+		if !ok {
+			level = "error"
+		}
+		out = append(out, sarifResult{
+			RuleID:  "health/" + string(r.Status),
+			Level:   level,
+			Message: sarifMessage{Text: r.ServerName + ": " + r.Message},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               r.ServerName,
+					FullyQualifiedName: r.ServerName,
+					Kind:               "module",
+				}},
+			}},
+			Properties: map[string]string{"server": r.ServerName, "type": r.Type},
+		})
+	}
+	for _, d := range drifts {
+		out = append(out, sarifResult{
+			RuleID:  "pin/" + driftType,
+			Level:   "error",
+			Message: sarifMessage{Text: d.Server + ": tools changed since approval: " + d.Message},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               d.Server,
+					FullyQualifiedName: d.Server,
+					Kind:               "module",
+				}},
+			}},
+			Properties: map[string]string{"server": d.Server},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: out}},
+	}
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// allStatuses lists every status so Prometheus series stay stable between runs
+var allStatuses = []CheckStatus{StatusHealthy, StatusUnhealthy, StatusTimeout, StatusError, StatusProtocolError}
+
+// Prometheus renders results in the Prometheus text exposition format,
+// suitable for node_exporter's textfile collector
+func Prometheus(results []CheckResult, drifts []Drift) []byte {
+	var b strings.Builder
+
+	b.WriteString("# HELP mseep_health_up Whether the server passed its last health check.\n")
+	b.WriteString("# TYPE mseep_health_up gauge\n")
+	for _, r := range results {
+		up := 0
+		if r.Status == StatusHealthy {
+			up = 1
+		}
+		fmt.Fprintf(&b, "mseep_health_up{server=\"%s\",type=\"%s\"} %d\n", promLabel(r.ServerName), promLabel(r.Type), up)
+	}
+
+	b.WriteString("# HELP mseep_health_status Status of the last health check; 1 for the current status.\n")
+	b.WriteString("# TYPE mseep_health_status gauge\n")
+	for _, r := range results {
+		for _, s := range allStatuses {
+			v := 0
+			if r.Status == s {
+				v = 1
+			}
+			fmt.Fprintf(&b, "mseep_health_status{server=\"%s\",status=\"%s\"} %d\n", promLabel(r.ServerName), s, v)
+		}
+	}
+
+	b.WriteString("# HELP mseep_health_duration_seconds Duration of the last health check.\n")
+	b.WriteString("# TYPE mseep_health_duration_seconds gauge\n")
+	for _, r := range results {
+		fmt.Fprintf(&b, "mseep_health_duration_seconds{server=\"%s\"} %s\n", promLabel(r.ServerName), seconds(r.Duration))
+	}
+
+	b.WriteString("# HELP mseep_health_last_check_timestamp_seconds Unix time of the last health check.\n")
+	b.WriteString("# TYPE mseep_health_last_check_timestamp_seconds gauge\n")
+	for _, r := range results {
+		fmt.Fprintf(&b, "mseep_health_last_check_timestamp_seconds{server=\"%s\"} %d\n", promLabel(r.ServerName), r.Timestamp.Unix())
+	}
+
+	drifted := map[string]bool{}
+	for _, d := range drifts {
+		drifted[d.Server] = true
+	}
+	b.WriteString("# HELP mseep_tool_drift Whether the server's tools changed since they were pinned.\n")
+	b.WriteString("# TYPE mseep_tool_drift gauge\n")
+	for _, r := range results {
+		v := 0
+		if drifted[r.ServerName] {
+			v = 1
+		}
+		fmt.Fprintf(&b, "mseep_tool_drift{server=\"%s\"} %d\n", promLabel(r.ServerName), v)
+	}
+
+	return []byte(b.String())
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// promLabel escapes a label value for the text exposition format
+func promLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
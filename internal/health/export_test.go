@@ -0,0 +1,116 @@
+package health
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+var exportResults = []CheckResult{
+	{ServerName: "fs", Type: "stdio", Status: StatusHealthy, Duration: 120 * time.Millisecond, Timestamp: time.Unix(1700000000, 0)},
+	{ServerName: `we"b`, Type: "http", Status: StatusUnhealthy, Message: "HTTP 503", Duration: time.Second, Timestamp: time.Unix(1700000000, 0)},
+	{ServerName: "bad", Type: "exec", Status: StatusError, Message: "no checker", Timestamp: time.Unix(1700000000, 0)},
+}
+
+var exportDrifts = []Drift{{Server: "fs", Message: "added exec"}}
+
+func TestJUnit(t *testing.T) {
+	out, err := JUnit(exportResults, exportDrifts, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("JUnit() error = %v", err)
+	}
+
+	var doc junitSuites
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, out)
+	}
+	suite := doc.Suites[0]
+	if suite.Tests != 4 || suite.Failures != 2 || suite.Errors != 1 {
+		t.Errorf("tests/failures/errors = %d/%d/%d, want 4/2/1", suite.Tests, suite.Failures, suite.Errors)
+	}
+	if suite.Cases[1].Failure == nil || suite.Cases[1].Failure.Message != "HTTP 503" {
+		t.Errorf("unexpected failure for unhealthy server: %+v", suite.Cases[1])
+	}
+	if suite.Cases[0].Failure != nil || suite.Cases[0].Error != nil {
+		t.Errorf("healthy server reported a problem: %+v", suite.Cases[0])
+	}
+	if c := suite.Cases[3]; c.Classname != "mseep.pin" || c.Failure == nil || c.Failure.Type != "tool_drift" {
+		t.Errorf("drifted server not reported as a failure: %+v", c)
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	b, err := SARIF(exportResults, exportDrifts, "test")
+	if err != nil {
+		t.Fatalf("SARIF() error = %v", err)
+	}
+
+	// Decode generically so the test checks the shape the schema requires,
+	// not just what our own structs round-trip
+	var log map[string]any
+	if err := json.Unmarshal(b, &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if log["version"] != "2.1.0" || log["$schema"] == nil {
+		t.Fatalf("unexpected SARIF envelope: %v", log)
+	}
+	runs, _ := log["runs"].([]any)
+	if len(runs) != 1 {
+		t.Fatalf("runs = %v, want one run", log["runs"])
+	}
+	run := runs[0].(map[string]any)
+	driver := run["tool"].(map[string]any)["driver"].(map[string]any)
+	if driver["name"] != "mseep" {
+		t.Errorf("driver name = %v, want mseep", driver["name"])
+	}
+	rules := map[string]bool{}
+	for _, r := range driver["rules"].([]any) {
+		rules[r.(map[string]any)["id"].(string)] = true
+	}
+
+	results := run["results"].([]any)
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want one per unhealthy or drifted server", len(results))
+	}
+	for i, want := range []struct{ rule, level, server string }{
+		{"health/unhealthy", "error", `we"b`},
+		{"health/error", "warning", "bad"},
+		{"pin/tool_drift", "error", "fs"},
+	} {
+		res := results[i].(map[string]any)
+		if res["ruleId"] != want.rule || res["level"] != want.level {
+			t.Errorf("result %d = %v/%v, want %s/%s", i, res["ruleId"], res["level"], want.rule, want.level)
+		}
+		if !rules[want.rule] {
+			t.Errorf("result %d references undeclared rule %s", i, want.rule)
+		}
+		if _, ok := res["message"].(map[string]any)["text"].(string); !ok {
+			t.Errorf("result %d has no message text: %v", i, res)
+		}
+		loc := res["locations"].([]any)[0].(map[string]any)["logicalLocations"].([]any)[0].(map[string]any)
+		if loc["fullyQualifiedName"] != want.server {
+			t.Errorf("result %d location = %v, want %s", i, loc["fullyQualifiedName"], want.server)
+		}
+	}
+}
+
+func TestPrometheus(t *testing.T) {
+	out := string(Prometheus(exportResults, exportDrifts))
+
+	for _, want := range []string{
+		`mseep_health_up{server="fs",type="stdio"} 1`,
+		`mseep_health_up{server="we\"b",type="http"} 0`,
+		`mseep_health_status{server="bad",status="error"} 1`,
+		`mseep_health_status{server="bad",status="healthy"} 0`,
+		`mseep_health_duration_seconds{server="fs"} 0.120`,
+		`mseep_health_last_check_timestamp_seconds{server="fs"} 1700000000`,
+		`mseep_tool_drift{server="fs"} 1`,
+		`mseep_tool_drift{server="bad"} 0`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+}
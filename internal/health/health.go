@@ -2,14 +2,20 @@ package health
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
 	"net"
 	"net/http"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
 	"mseep/internal/config"
+	"mseep/internal/launchenv"
+	"mseep/internal/mcp"
 )
 
 // CheckResult represents the result of a health check
@@ -20,6 +26,11 @@ type CheckResult struct {
 	Message    string        `json:"message"`
 	Duration   time.Duration `json:"duration"`
 	Timestamp  time.Time     `json:"timestamp"`
+	// Stdout and Stderr hold the tail of a failed stdio server's output
+	Stdout string `json:"stdout,omitempty"`
+	Stderr string `json:"stderr,omitempty"`
+	// Attempts is how many times the check ran, counting retries
+	Attempts int `json:"attempts,omitempty"`
 }
 
 // CheckStatus represents the health check status
@@ -30,6 +41,8 @@ const (
 	StatusUnhealthy CheckStatus = "unhealthy"
 	StatusTimeout   CheckStatus = "timeout"
 	StatusError     CheckStatus = "error"
+	// StatusProtocolError means the server ran but did not speak MCP correctly
+	StatusProtocolError CheckStatus = "protocol_error"
 )
 
 // Checker interface for different health check types
@@ -40,20 +53,86 @@ type Checker interface {
 // Manager manages health checks for multiple servers
 type Manager struct {
 	checkers map[string]Checker
+	// Env is the client launch environment stdio servers are started in
+	Env *launchenv.Env
+	// Concurrency caps simultaneous checks; zero uses DefaultConcurrency
+	Concurrency int
+	// Rand returns a number in [0, 1) used to jitter retry backoff; nil
+	// uses math/rand
+	Rand func() float64
 }
 
-// NewManager creates a new health check manager
+// NewManager creates a new health check manager that launches stdio
+// servers in the current shell's environment
 func NewManager() *Manager {
-	return &Manager{
-		checkers: map[string]Checker{
-			"stdio": &StdioChecker{},
-			"http":  &HTTPChecker{},
-			"tcp":   &TCPChecker{},
-		},
+	env, _ := launchenv.For("")
+	return NewManagerFor(env)
+}
+
+// NewManagerFor creates a health check manager that launches stdio servers
+// the way env's client would
+func NewManagerFor(env *launchenv.Env) *Manager {
+	m := &Manager{Env: env, checkers: map[string]Checker{}}
+	for typ, newChecker := range builtins {
+		m.checkers[typ] = newChecker(env)
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for typ, c := range registry {
+		m.checkers[typ] = c
+	}
+	return m
+}
+
+// builtins creates the built-in checkers for a launch environment
+var builtins = map[string]func(env *launchenv.Env) Checker{
+	"stdio":    func(env *launchenv.Env) Checker { return &StdioChecker{Env: env} },
+	"http":     func(*launchenv.Env) Checker { return &HTTPChecker{} },
+	"tcp":      func(*launchenv.Env) Checker { return &TCPChecker{} },
+	"mcp-http": func(*launchenv.Env) Checker { return &MCPHTTPChecker{} },
+	"mcp-sse":  func(*launchenv.Env) Checker { return &MCPHTTPChecker{SSE: true} },
+	"exec":     func(env *launchenv.Env) Checker { return &ExecChecker{Env: env} },
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]Checker{}
+)
+
+// Register makes a custom checker available to every manager created
+// afterwards as health check type typ, typically from an init function.
+// It panics if typ is empty, built in, or already registered.
+func Register(typ string, c Checker) {
+	if typ == "" || c == nil {
+		panic("health: Register with empty type or nil checker")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, builtin := builtins[typ]; builtin {
+		panic("health: Register of builtin checker type " + typ)
+	}
+	if _, dup := registry[typ]; dup {
+		panic("health: Register of duplicate checker type " + typ)
 	}
+	registry[typ] = c
+}
+
+// Register adds or replaces the checker for type typ on this manager only
+func (m *Manager) Register(typ string, c Checker) {
+	m.checkers[typ] = c
 }
 
-// CheckServer performs a health check on a single server
+// Defaults for health checks that leave limits unset
+const (
+	DefaultTimeout     = 5 * time.Second // overall budget per server
+	DefaultBackoff     = 500 * time.Millisecond
+	DefaultConcurrency = 10
+	maxBackoff         = 10 * time.Second
+)
+
+// CheckServer performs a health check on a single server. Each attempt is
+// bounded by the spec's attempt timeout and all attempts, including the
+// jittered exponential backoff between them, by its overall timeout.
 func (m *Manager) CheckServer(ctx context.Context, server config.Server) CheckResult {
 	start := time.Now()
 	
@@ -62,9 +141,8 @@ func (m *Manager) CheckServer(ctx context.Context, server config.Server) CheckRe
 	if healthSpec == nil {
 		// Default to stdio check
 		healthSpec = &config.HealthSpec{
-			Type:      "stdio",
-			TimeoutMs: 5000,
-			Retries:   1,
+			Type:    "stdio",
+			Retries: 1,
 		}
 	}
 	
@@ -80,95 +158,145 @@ func (m *Manager) CheckServer(ctx context.Context, server config.Server) CheckRe
 		}
 	}
 	
-	// Apply timeout
-	timeout := time.Duration(healthSpec.TimeoutMs) * time.Millisecond
-	if timeout == 0 {
-		timeout = 5 * time.Second
+	// Apply the overall budget
+	budget := time.Duration(healthSpec.TimeoutMs) * time.Millisecond
+	if budget <= 0 {
+		budget = DefaultTimeout
+	}
+	attemptTimeout := time.Duration(healthSpec.AttemptTimeoutMs) * time.Millisecond
+	if attemptTimeout <= 0 || attemptTimeout > budget {
+		attemptTimeout = budget
+	}
+	backoff := time.Duration(healthSpec.BackoffMs) * time.Millisecond
+	if backoff <= 0 {
+		backoff = DefaultBackoff
 	}
 	
-	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	checkCtx, cancel := context.WithTimeout(ctx, budget)
 	defer cancel()
 	
 	// Perform check with retries
 	retries := healthSpec.Retries
-	if retries == 0 {
+	if retries <= 0 {
 		retries = 1
 	}
 	
 	var lastResult CheckResult
+	attempts := 0
 	for i := 0; i < retries; i++ {
 		if i > 0 {
-			// Brief delay between retries
+			// Back off exponentially, with jitter, between retries
 			select {
 			case <-checkCtx.Done():
-				return CheckResult{
-					ServerName: server.Name,
-					Type:       healthSpec.Type,
-					Status:     StatusTimeout,
-					Message:    "health check timed out during retries",
-					Duration:   time.Since(start),
-					Timestamp:  time.Now(),
-				}
-			case <-time.After(500 * time.Millisecond):
+				lastResult.Status = StatusTimeout
+				lastResult.Message = fmt.Sprintf("health check budget of %v exhausted after %d attempts; last: %s", budget, attempts, lastResult.Message)
+				lastResult.Attempts = attempts
+				lastResult.Duration = time.Since(start)
+				lastResult.Timestamp = time.Now()
+				return lastResult
+			case <-time.After(m.jitter(backoff)):
+			}
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
 			}
 		}
 		
-		lastResult = checker.Check(checkCtx, server)
-		if lastResult.Status == StatusHealthy {
+		attemptCtx, cancelAttempt := context.WithTimeout(checkCtx, attemptTimeout)
+		lastResult = checker.Check(attemptCtx, server)
+		cancelAttempt()
+		attempts++
+		if lastResult.Status == StatusHealthy || lastResult.Status == StatusError {
+			// Misconfigured checks fail the same way every time
 			break
 		}
 	}
 	
+	lastResult.Attempts = attempts
 	lastResult.Duration = time.Since(start)
 	lastResult.Timestamp = time.Now()
 	return lastResult
 }
 
-// CheckServers performs health checks on multiple servers concurrently
+// jitter spreads a backoff of d over [d/2, d) so servers that fail together
+// do not all retry together
+func (m *Manager) jitter(d time.Duration) time.Duration {
+	rnd := m.Rand
+	if rnd == nil {
+		rnd = rand.Float64
+	}
+	half := d / 2
+	return half + time.Duration(rnd()*float64(d-half))
+}
+
+// Open launches the server and completes the MCP handshake, returning a
+// session ready for further requests. Callers must Close the session.
+func (m *Manager) Open(ctx context.Context, server config.Server) (*Session, error) {
+	var sess *Session
+	var err error
+	switch {
+	case server.Health != nil && (server.Health.Type == "mcp-http" || server.Health.Type == "mcp-sse"):
+		sess, err = StartRemote(ctx, *server.Health)
+	case server.Transport == "" || server.Transport == "stdio":
+		sess, err = StartStdio(ctx, server, m.Env)
+	default:
+		return nil, fmt.Errorf("cannot open MCP session over %s transport without an mcp-http or mcp-sse health check", server.Transport)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := sess.Client.Initialize(ctx); err != nil {
+		_, msg := sess.classify(ctx, err)
+		sess.Close()
+		return nil, fmt.Errorf("%s", msg)
+	}
+	return sess, nil
+}
+
+// CheckServers performs health checks on multiple servers, running at
+// most the manager's Concurrency at once. Checks still queued when ctx is
+// cancelled are reported as cancelled without running.
 func (m *Manager) CheckServers(ctx context.Context, servers []config.Server) []CheckResult {
 	results := make([]CheckResult, len(servers))
 	
-	// Use a semaphore to limit concurrent checks
-	sem := make(chan struct{}, 10)
-	done := make(chan struct{}, len(servers))
+	limit := m.Concurrency
+	if limit <= 0 {
+		limit = DefaultConcurrency
+	}
+	sem := make(chan struct{}, limit)
 	
+	var wg sync.WaitGroup
 	for i, server := range servers {
+		wg.Add(1)
 		go func(idx int, srv config.Server) {
-			sem <- struct{}{}
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results[idx] = CheckResult{
+					ServerName: srv.Name,
+					Type:       "unknown",
+					Status:     StatusTimeout,
+					Message:    "health check cancelled",
+					Timestamp:  time.Now(),
+				}
+				return
+			}
 			defer func() { <-sem }()
-			defer func() { done <- struct{}{} }()
 			
 			results[idx] = m.CheckServer(ctx, srv)
 		}(i, server)
 	}
-	
-	// Wait for all checks to complete
-	for i := 0; i < len(servers); i++ {
-		select {
-		case <-done:
-		case <-ctx.Done():
-			// Fill remaining results with timeout status
-			for j := i; j < len(servers); j++ {
-				if results[j].ServerName == "" {
-					results[j] = CheckResult{
-						ServerName: servers[j].Name,
-						Type:       "unknown",
-						Status:     StatusTimeout,
-						Message:    "health check cancelled",
-						Duration:   0,
-						Timestamp:  time.Now(),
-					}
-				}
-			}
-			return results
-		}
-	}
+	wg.Wait()
 	
 	return results
 }
 
-// StdioChecker performs health checks by executing the server command
-type StdioChecker struct{}
+// StdioChecker performs health checks by launching the server command and
+// completing the MCP initialize handshake over stdin/stdout, followed by
+// the probe tool call when one is configured
+type StdioChecker struct {
+	Env *launchenv.Env
+}
 
 func (c *StdioChecker) Check(ctx context.Context, server config.Server) CheckResult {
 	result := CheckResult{
@@ -178,64 +306,262 @@ func (c *StdioChecker) Check(ctx context.Context, server config.Server) CheckRes
 		Message:    "",
 	}
 	
-	if server.Command == "" {
-		result.Message = "no command specified"
+	sess, err := StartStdio(ctx, server, c.Env)
+	if err != nil {
+		result.Message = err.Error()
 		return result
 	}
 	
-	// Create command
-	cmd := exec.CommandContext(ctx, server.Command, server.Args...)
+	info, err := sess.Client.Initialize(ctx)
+	if err != nil {
+		result.Status, result.Message = sess.classify(ctx, err)
+		sess.Close()
+		// Closing waits for exit, so the captured output is complete
+		result.Stdout, result.Stderr = sess.Output()
+		return result
+	}
 	
-	// Set environment variables
-	if len(server.Env) > 0 {
-		env := make([]string, 0, len(server.Env))
-		for k, v := range server.Env {
-			env = append(env, fmt.Sprintf("%s=%s", k, v))
+	result.Status = StatusHealthy
+	result.Message = fmt.Sprintf("MCP handshake ok: %s %s (protocol %s)",
+		info.ServerInfo.Name, info.ServerInfo.Version, info.ProtocolVersion)
+	if server.Health != nil && server.Health.Probe != nil {
+		status, msg := RunProbe(ctx, sess.Client, *server.Health.Probe)
+		if status == StatusHealthy {
+			msg = result.Message + "; " + msg
 		}
-		cmd.Env = env
+		result.Status, result.Message = status, msg
+	}
+	if err := sess.Close(); err != nil {
+		result.Message += "; " + err.Error()
+	}
+	if result.Status != StatusHealthy {
+		result.Stdout, result.Stderr = sess.Output()
 	}
 	
-	// Try to start the process
-	err := cmd.Start()
+	return result
+}
+
+// Session is an MCP client attached to a launched stdio server or a
+// remote endpoint
+type Session struct {
+	Client *mcp.Client
+	// cmd is nil for remote servers
+	cmd     *exec.Cmd
+	exited  chan struct{}
+	exitErr error
+	stdout  *tailBuffer
+	stderr  *tailBuffer
+}
+
+// shutdownGrace is how long a server may take to exit after its stdin closes
+const shutdownGrace = 2 * time.Second
+
+// StartStdio launches the server command with pipes attached to an MCP
+// client, in env's launch environment (the current shell when nil). The
+// handshake is left to the caller. Launch failures are returned as errors;
+// the process is killed when ctx is done.
+func StartStdio(ctx context.Context, server config.Server, env *launchenv.Env) (*Session, error) {
+	if server.Command == "" {
+		return nil, fmt.Errorf("no command specified")
+	}
+	if env == nil {
+		env, _ = launchenv.For("")
+	}
+	
+	// Resolve the command on the client's PATH rather than mseep's
+	launch := env.ForServer(server.Env)
+	path, err := launch.LookPath(server.Command)
 	if err != nil {
-		result.Message = fmt.Sprintf("failed to start command: %v", err)
-		return result
+		return nil, fmt.Errorf("failed to start command: %v", err)
+	}
+	
+	// Create command
+	cmd := exec.CommandContext(ctx, path, server.Args...)
+	cmd.Args[0] = server.Command
+	// Servers launched via npx/uvx leave children holding stdout open
+	cmd.WaitDelay = shutdownGrace
+	cmd.Env = launch.Vars
+	cmd.Dir = env.WorkDir
+	
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stdin: %v", err)
+	}
+	// Use an in-process pipe so Wait does not close stdout while we read it
+	pr, pw := io.Pipe()
+	stdout, stderr := newTailBuffer(MaxOutputBytes), newTailBuffer(MaxOutputBytes)
+	cmd.Stdout = io.MultiWriter(pw, stdout)
+	cmd.Stderr = stderr
+	
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start command: %v", err)
 	}
 	
-	// Wait briefly to see if the process crashes immediately
-	done := make(chan error, 1)
+	s := &Session{
+		Client: mcp.NewClient(mcp.NewStdioTransport(pr, stdin)),
+		cmd:    cmd,
+		exited: make(chan struct{}),
+		stdout: stdout,
+		stderr: stderr,
+	}
 	go func() {
-		done <- cmd.Wait()
+		s.exitErr = cmd.Wait()
+		pw.Close()
+		close(s.exited)
 	}()
-	
+	return s, nil
+}
+
+// classify maps a handshake error to a status, separating timeouts, early
+// exits and protocol violations
+func (s *Session) classify(ctx context.Context, err error) (CheckStatus, string) {
+	switch {
+	case ctx.Err() != nil:
+		return StatusTimeout, "no initialize response before timeout"
+	case errors.Is(err, mcp.ErrClosed) && (s == nil || s.cmd == nil):
+		return StatusUnhealthy, "server closed the connection before handshake"
+	case errors.Is(err, mcp.ErrClosed):
+		// exitErr is only safe to read once exited is closed
+		select {
+		case <-s.exited:
+			if s.exitErr != nil {
+				return StatusUnhealthy, fmt.Sprintf("command exited before handshake: %v", s.exitErr)
+			}
+		case <-time.After(shutdownGrace):
+		}
+		return StatusUnhealthy, "command exited before handshake"
+	case mcp.IsProtocolError(err):
+		return StatusProtocolError, err.Error()
+	default:
+		return StatusUnhealthy, fmt.Sprintf("handshake failed: %v", err)
+	}
+}
+
+// Close shuts the server down: stdin is closed and the process is given a
+// grace period to exit before being killed
+func (s *Session) Close() error {
+	if s.cmd == nil {
+		return s.Client.Close()
+	}
+	s.Client.Close()
 	select {
-	case err := <-done:
-		if err != nil {
-			result.Status = StatusUnhealthy
-			result.Message = fmt.Sprintf("command exited with error: %v", err)
-		} else {
-			// Command completed successfully (unusual for MCP servers)
-			result.Status = StatusHealthy
-			result.Message = "command completed successfully"
+	case <-s.exited:
+		return nil
+	case <-time.After(shutdownGrace):
+	}
+	if s.cmd.Process != nil {
+		s.cmd.Process.Kill()
+	}
+	<-s.exited
+	return fmt.Errorf("killed after not exiting within %v of stdin closing", shutdownGrace)
+}
+
+// Output returns the tail of a stdio server's stdout and stderr
+func (s *Session) Output() (stdout, stderr string) {
+	if s.cmd == nil {
+		return "", ""
+	}
+	return s.stdout.String(), s.stderr.String()
+}
+
+// MCPHTTPChecker completes the MCP handshake with a remote server over
+// streamable HTTP, or over the legacy HTTP+SSE transport when SSE is set
+type MCPHTTPChecker struct {
+	SSE bool
+}
+
+func (c *MCPHTTPChecker) Check(ctx context.Context, server config.Server) CheckResult {
+	result := CheckResult{
+		ServerName: server.Name,
+		Type:       "mcp-http",
+		Status:     StatusError,
+		Message:    "",
+	}
+	if c.SSE {
+		result.Type = "mcp-sse"
+	}
+	
+	healthSpec := server.Health
+	if healthSpec == nil || healthSpec.URL == "" {
+		result.Message = "no MCP endpoint URL specified"
+		return result
+	}
+	headers, err := RemoteHeaders(*healthSpec)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	
+	sess, err := openRemote(ctx, healthSpec.URL, headers, c.SSE)
+	if err != nil {
+		result.Status, result.Message = sess.classify(ctx, err)
+		return result
+	}
+	defer sess.Close()
+	
+	info, err := sess.Client.Initialize(ctx)
+	if err != nil {
+		result.Status, result.Message = sess.classify(ctx, err)
+		return result
+	}
+	
+	result.Status = StatusHealthy
+	result.Message = fmt.Sprintf("MCP handshake ok: %s %s (protocol %s)",
+		info.ServerInfo.Name, info.ServerInfo.Version, info.ProtocolVersion)
+	if healthSpec.Probe != nil {
+		status, msg := RunProbe(ctx, sess.Client, *healthSpec.Probe)
+		if status == StatusHealthy {
+			msg = result.Message + "; " + msg
 		}
-	case <-time.After(1 * time.Second):
-		// Process is still running after 1 second, consider it healthy
-		result.Status = StatusHealthy
-		result.Message = "command started successfully"
-		
-		// Try to terminate the process gracefully
-		if cmd.Process != nil {
-			cmd.Process.Kill()
+		result.Status, result.Message = status, msg
+	}
+	return result
+}
+
+// RemoteHeaders builds the request headers for a remote check, resolving
+// secret references in header values and the bearer token
+func RemoteHeaders(spec config.HealthSpec) (http.Header, error) {
+	headers := http.Header{}
+	for k, v := range spec.Headers {
+		resolved, err := config.ResolveSecret(v)
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %v", k, err)
 		}
-	case <-ctx.Done():
-		result.Status = StatusTimeout
-		result.Message = "health check timed out"
-		if cmd.Process != nil {
-			cmd.Process.Kill()
+		headers.Set(k, resolved)
+	}
+	if spec.BearerToken != "" {
+		token, err := config.ResolveSecret(spec.BearerToken)
+		if err != nil {
+			return nil, fmt.Errorf("bearer token: %v", err)
 		}
+		headers.Set("Authorization", "Bearer "+token)
 	}
-	
-	return result
+	return headers, nil
+}
+
+// StartRemote connects to the endpoint named by an mcp-http or mcp-sse
+// health spec. The handshake is left to the caller.
+func StartRemote(ctx context.Context, spec config.HealthSpec) (*Session, error) {
+	if spec.URL == "" {
+		return nil, fmt.Errorf("no MCP endpoint URL specified")
+	}
+	headers, err := RemoteHeaders(spec)
+	if err != nil {
+		return nil, err
+	}
+	return openRemote(ctx, spec.URL, headers, spec.Type == "mcp-sse")
+}
+
+func openRemote(ctx context.Context, url string, headers http.Header, sse bool) (*Session, error) {
+	client := &http.Client{}
+	if !sse {
+		return &Session{Client: mcp.NewClient(mcp.NewHTTPTransport(url, client, headers))}, nil
+	}
+	t, err := mcp.NewSSETransport(ctx, url, client, headers)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{Client: mcp.NewClient(t)}, nil
 }
 
 // HTTPChecker performs HTTP health checks
@@ -255,9 +581,8 @@ func (c *HTTPChecker) Check(ctx context.Context, server config.Server) CheckResu
 		return result
 	}
 	
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
+	// The attempt deadline on ctx bounds the request
+	client := &http.Client{}
 	
 	req, err := http.NewRequestWithContext(ctx, "GET", healthSpec.URL, nil)
 	if err != nil {
@@ -312,9 +637,8 @@ func (c *TCPChecker) Check(ctx context.Context, server config.Server) CheckResul
 		address = strings.TrimPrefix(address, "tcp://")
 	}
 	
-	dialer := &net.Dialer{
-		Timeout: 5 * time.Second,
-	}
+	// The attempt deadline on ctx bounds the dial
+	dialer := &net.Dialer{}
 	
 	conn, err := dialer.DialContext(ctx, "tcp", address)
 	if err != nil {
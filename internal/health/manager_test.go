@@ -0,0 +1,107 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mseep/internal/config"
+)
+
+// blockingChecker waits for its context and counts attempts
+type blockingChecker struct {
+	attempts atomic.Int32
+	running  atomic.Int32
+	peak     atomic.Int32
+	hold     time.Duration
+}
+
+func (c *blockingChecker) Check(ctx context.Context, server config.Server) CheckResult {
+	c.attempts.Add(1)
+	n := c.running.Add(1)
+	defer c.running.Add(-1)
+	for {
+		p := c.peak.Load()
+		if n <= p || c.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	select {
+	case <-ctx.Done():
+		return CheckResult{ServerName: server.Name, Status: StatusTimeout, Message: "attempt timed out"}
+	case <-time.After(c.hold):
+		return CheckResult{ServerName: server.Name, Status: StatusHealthy}
+	}
+}
+
+func TestCheckServerAttemptTimeout(t *testing.T) {
+	checker := &blockingChecker{hold: time.Hour}
+	m := &Manager{checkers: map[string]Checker{"fake": checker}}
+	server := config.Server{Name: "slow", Health: &config.HealthSpec{
+		Type: "fake", TimeoutMs: 2000, AttemptTimeoutMs: 50, Retries: 3, BackoffMs: 10,
+	}}
+
+	start := time.Now()
+	r := m.CheckServer(context.Background(), server)
+	if r.Status != StatusTimeout || r.Attempts != 3 || checker.attempts.Load() != 3 {
+		t.Fatalf("status %s after %d attempts (%d run), want timeout after 3", r.Status, r.Attempts, checker.attempts.Load())
+	}
+	// Three 50ms attempts plus at most 10ms and 20ms of backoff, well under the budget
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %v; attempts should not use the whole budget", elapsed)
+	}
+}
+
+func TestCheckServerBudget(t *testing.T) {
+	checker := &blockingChecker{hold: time.Hour}
+	m := &Manager{checkers: map[string]Checker{"fake": checker}}
+	server := config.Server{Name: "slow", Health: &config.HealthSpec{
+		Type: "fake", TimeoutMs: 100, AttemptTimeoutMs: 40, Retries: 10, BackoffMs: 30,
+	}}
+
+	r := m.CheckServer(context.Background(), server)
+	if r.Status != StatusTimeout {
+		t.Fatalf("status = %s, want timeout", r.Status)
+	}
+	if n := checker.attempts.Load(); n >= 10 {
+		t.Errorf("ran %d attempts; the budget should stop retries early", n)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	for _, r := range []float64{0, 0.5, 0.999999} {
+		m := &Manager{Rand: func() float64 { return r }}
+		for d := DefaultBackoff; d <= maxBackoff; d *= 2 {
+			if got := m.jitter(d); got < d/2 || got >= d {
+				t.Errorf("jitter(%v) with rand %v = %v, want within [%v, %v)", d, r, got, d/2, d)
+			}
+		}
+	}
+
+	m := &Manager{}
+	for i := 0; i < 1000; i++ {
+		if got := m.jitter(maxBackoff); got < maxBackoff/2 || got >= maxBackoff {
+			t.Fatalf("jitter(%v) = %v, want within [%v, %v)", maxBackoff, got, maxBackoff/2, maxBackoff)
+		}
+	}
+}
+
+func TestCheckServersConcurrency(t *testing.T) {
+	checker := &blockingChecker{hold: 20 * time.Millisecond}
+	m := &Manager{checkers: map[string]Checker{"fake": checker}, Concurrency: 2}
+	var servers []config.Server
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		servers = append(servers, config.Server{Name: name, Health: &config.HealthSpec{Type: "fake"}})
+	}
+
+	results := m.CheckServers(context.Background(), servers)
+	for i, r := range results {
+		if r.ServerName != servers[i].Name || r.Status != StatusHealthy {
+			t.Errorf("result %d = %s %s", i, r.ServerName, r.Status)
+		}
+	}
+	if peak := checker.peak.Load(); peak > 2 {
+		t.Errorf("peak concurrency %d, want at most 2", peak)
+	}
+}
@@ -0,0 +1,48 @@
+package health
+
+import (
+	"strings"
+	"sync"
+)
+
+// MaxOutputBytes bounds how much of a server's stdout and stderr is kept
+// with a check result
+const MaxOutputBytes = 4096
+
+// tailBuffer keeps the last max bytes written to it
+type tailBuffer struct {
+	mu        sync.Mutex
+	max       int
+	buf       []byte
+	truncated bool
+}
+
+func newTailBuffer(max int) *tailBuffer {
+	return &tailBuffer{max: max}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.max; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+// String returns the kept output, starting at a line boundary when earlier
+// output was dropped
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := string(b.buf)
+	if b.truncated {
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			s = s[i+1:]
+		}
+		s = "[earlier output truncated]\n" + s
+	}
+	return s
+}
@@ -0,0 +1,23 @@
+package health
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTailBuffer(t *testing.T) {
+	b := newTailBuffer(16)
+	b.Write([]byte("first line\n"))
+	if got := b.String(); got != "first line\n" {
+		t.Errorf("String() = %q", got)
+	}
+
+	b.Write([]byte("second\nthird\n"))
+	got := b.String()
+	if !strings.HasPrefix(got, "[earlier output truncated]\n") || !strings.HasSuffix(got, "third\n") {
+		t.Errorf("String() = %q, want truncation marker and last line", got)
+	}
+	if strings.Contains(got, "first") {
+		t.Errorf("String() kept dropped output: %q", got)
+	}
+}
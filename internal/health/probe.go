@@ -0,0 +1,178 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"mseep/internal/config"
+	"mseep/internal/mcp"
+)
+
+// RunProbe calls the probe tool over an initialized client and checks the
+// result against the probe's assertions
+func RunProbe(ctx context.Context, client *mcp.Client, probe config.ProbeSpec) (CheckStatus, string) {
+	if probe.Tool == "" {
+		return StatusError, "probe has no tool"
+	}
+	if _, err := regexp.Compile(probe.Regex); err != nil {
+		return StatusError, fmt.Sprintf("probe %s: invalid regex: %v", probe.Tool, err)
+	}
+	res, err := client.CallTool(ctx, probe.Tool, probe.Arguments)
+	switch {
+	case ctx.Err() != nil:
+		return StatusTimeout, fmt.Sprintf("probe %s: no response before timeout", probe.Tool)
+	case mcp.IsProtocolError(err):
+		return StatusProtocolError, fmt.Sprintf("probe %s: %v", probe.Tool, err)
+	case err != nil:
+		return StatusUnhealthy, fmt.Sprintf("probe %s: %v", probe.Tool, err)
+	case res.IsError:
+		return StatusUnhealthy, fmt.Sprintf("probe %s returned an error: %s", probe.Tool, oneLine(res.Text()))
+	}
+
+	if err := assertProbe(res, probe); err != nil {
+		return StatusUnhealthy, fmt.Sprintf("probe %s: %v", probe.Tool, err)
+	}
+	return StatusHealthy, fmt.Sprintf("probe %s ok", probe.Tool)
+}
+
+// assertProbe checks a tool result against the JSONPath, Equals and Regex
+// assertions of probe
+func assertProbe(res *mcp.ToolResult, probe config.ProbeSpec) error {
+	subject := res.Text()
+	if probe.JSONPath != "" {
+		var doc interface{}
+		if err := json.Unmarshal(res.Raw, &doc); err != nil {
+			return fmt.Errorf("invalid result: %v", err)
+		}
+		v, err := evalPath(doc, probe.JSONPath)
+		if err != nil {
+			return err
+		}
+		switch {
+		case probe.Equals != nil:
+			if !jsonEqual(v, probe.Equals) {
+				return fmt.Errorf("%s is %s, want %s", probe.JSONPath, jsonText(v), jsonText(probe.Equals))
+			}
+		case v == nil || v == false:
+			return fmt.Errorf("%s is %s", probe.JSONPath, jsonText(v))
+		}
+		if s, ok := v.(string); ok {
+			subject = s
+		} else {
+			subject = jsonText(v)
+		}
+	}
+
+	if probe.Regex != "" {
+		re, err := regexp.Compile(probe.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex: %v", err)
+		}
+		if !re.MatchString(subject) {
+			return fmt.Errorf("result %q does not match /%s/", oneLine(subject), probe.Regex)
+		}
+	}
+	return nil
+}
+
+// evalPath resolves a JSON path of the form $.a.b[0]["c d"] against doc.
+// A string reached mid-path that holds JSON is parsed and descended into,
+// since tools usually return JSON as text content.
+func evalPath(doc interface{}, path string) (interface{}, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	cur := doc
+	walked := "$"
+	for rest != "" {
+		var key string
+		index := -1
+		switch {
+		case rest[0] == '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key, rest = rest[:end], rest[end:]
+			if key == "" {
+				return nil, fmt.Errorf("invalid path %q: empty key after %s", path, walked)
+			}
+			walked += "." + key
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing ]", path)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			walked += "[" + inner + "]"
+			if unq, err := strconv.Unquote(inner); err == nil {
+				key = unq
+			} else if n, err := strconv.Atoi(inner); err == nil {
+				index = n
+			} else {
+				return nil, fmt.Errorf("invalid path %q: bad subscript [%s]", path, inner)
+			}
+		default:
+			return nil, fmt.Errorf("invalid path %q at %q", path, rest)
+		}
+
+		if s, ok := cur.(string); ok {
+			var parsed interface{}
+			if json.Unmarshal([]byte(s), &parsed) == nil {
+				cur = parsed
+			}
+		}
+
+		if index >= 0 {
+			arr, ok := cur.([]interface{})
+			if !ok || index >= len(arr) {
+				return nil, fmt.Errorf("%s not found", walked)
+			}
+			cur = arr[index]
+			continue
+		}
+		obj, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s not found", walked)
+		}
+		v, ok := obj[key]
+		if !ok {
+			return nil, fmt.Errorf("%s not found", walked)
+		}
+		cur = v
+	}
+	return cur, nil
+}
+
+// jsonEqual compares two decoded JSON values, treating all numbers alike
+func jsonEqual(a, b interface{}) bool {
+	var na, nb interface{}
+	json.Unmarshal([]byte(jsonText(a)), &na)
+	json.Unmarshal([]byte(jsonText(b)), &nb)
+	return reflect.DeepEqual(na, nb)
+}
+
+func jsonText(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// oneLine shortens s to its first line for use in a message
+func oneLine(s string) string {
+	line, _, more := strings.Cut(strings.TrimSpace(s), "\n")
+	if more || len(line) > 120 {
+		if len(line) > 120 {
+			line = line[:120]
+		}
+		line += "…"
+	}
+	return line
+}
@@ -0,0 +1,66 @@
+package health
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"mseep/internal/config"
+	"mseep/internal/mcp"
+)
+
+func TestEvalPath(t *testing.T) {
+	var doc interface{}
+	json.Unmarshal([]byte(`{"content":[{"type":"text","text":"{\"user\":{\"login\":\"octo\"},\"ok\":true}"}],"a b":1}`), &doc)
+
+	tests := []struct {
+		path string
+		want interface{}
+		err  string
+	}{
+		{"$.content[0].type", "text", ""},
+		{"$.content[0].text.user.login", "octo", ""},
+		{`$["a b"]`, float64(1), ""},
+		{"$.content[1]", nil, "$.content[1] not found"},
+		{"$.missing.x", nil, "$.missing not found"},
+		{"$.content[", nil, "missing ]"},
+	}
+	for _, tt := range tests {
+		got, err := evalPath(doc, tt.path)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("evalPath(%q) error = %v, want %q", tt.path, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("evalPath(%q) = %v, %v, want %v", tt.path, got, err, tt.want)
+		}
+	}
+}
+
+func TestAssertProbe(t *testing.T) {
+	raw := json.RawMessage(`{"content":[{"type":"text","text":"{\"authenticated\":false,\"user\":\"octo\",\"count\":3}"}]}`)
+	res := &mcp.ToolResult{Raw: raw}
+	json.Unmarshal(raw, res)
+
+	tests := []struct {
+		probe config.ProbeSpec
+		ok    bool
+	}{
+		{config.ProbeSpec{}, true},
+		{config.ProbeSpec{Regex: `"user":"octo"`}, true},
+		{config.ProbeSpec{Regex: `expired`}, false},
+		{config.ProbeSpec{JSONPath: "$.content[0].text.user"}, true},
+		{config.ProbeSpec{JSONPath: "$.content[0].text.authenticated"}, false},
+		{config.ProbeSpec{JSONPath: "$.content[0].text.authenticated", Equals: false}, true},
+		{config.ProbeSpec{JSONPath: "$.content[0].text.count", Equals: 3}, true},
+		{config.ProbeSpec{JSONPath: "$.content[0].text.user", Regex: "^oc"}, true},
+		{config.ProbeSpec{JSONPath: "$.content[0].text.token"}, false},
+	}
+	for _, tt := range tests {
+		if err := assertProbe(res, tt.probe); (err == nil) != tt.ok {
+			t.Errorf("assertProbe(%+v) = %v, want ok=%v", tt.probe, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"mseep/internal/config"
+	"mseep/internal/health"
+)
+
+// Default retention limits for the history store
+const (
+	DefaultMaxAge     = 30 * 24 * time.Hour
+	DefaultMaxEntries = 500 // per server
+)
+
+// Store is an append-only JSON lines log of health check results
+type Store struct {
+	Path string
+	// MaxAge drops entries older than this; zero keeps everything
+	MaxAge time.Duration
+	// MaxEntries keeps at most this many recent entries per server; zero keeps everything
+	MaxEntries int
+}
+
+// ServerStats summarizes a server's history
+type ServerStats struct {
+	Server        string        `json:"server"`
+	Checks        int           `json:"checks"`
+	Passed        int           `json:"passed"`
+	PassRate      float64       `json:"passRate"`
+	P50           time.Duration `json:"p50"`
+	P95           time.Duration `json:"p95"`
+	LastStatus    string        `json:"lastStatus"`
+	LastCheckAt   time.Time     `json:"lastCheckAt"`
+	LastFailure   string        `json:"lastFailure,omitempty"`
+	LastFailureAt time.Time     `json:"lastFailureAt,omitempty"`
+}
+
+// Open returns the store in the mseep config directory with default retention
+func Open() (*Store, error) {
+	dir, err := config.EnsureDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{
+		Path:       filepath.Join(dir, "health-history.jsonl"),
+		MaxAge:     DefaultMaxAge,
+		MaxEntries: DefaultMaxEntries,
+	}, nil
+}
+
+// Append records results and applies the retention limits
+func (s *Store) Append(results []health.CheckResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, r := range results {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return s.prune()
+}
+
+// readAll loads every entry, skipping lines that fail to parse
+func (s *Store) readAll() ([]health.CheckResult, error) {
+	f, err := os.Open(s.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []health.CheckResult
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var r health.CheckResult
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			continue
+		}
+		entries = append(entries, r)
+	}
+	return entries, sc.Err()
+}
+
+// prune rewrites the log without entries beyond the retention limits. The
+// file is only rewritten when something is dropped.
+func (s *Store) prune() error {
+	entries, err := s.readAll()
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Time{}
+	if s.MaxAge > 0 {
+		cutoff = time.Now().Add(-s.MaxAge)
+	}
+
+	// Walk newest first so the per-server cap keeps recent entries
+	perServer := map[string]int{}
+	keep := make([]bool, len(entries))
+	dropped := false
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Timestamp.Before(cutoff) || (s.MaxEntries > 0 && perServer[e.ServerName] >= s.MaxEntries) {
+			dropped = true
+			continue
+		}
+		perServer[e.ServerName]++
+		keep[i] = true
+	}
+	if !dropped {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for i, e := range entries {
+		if !keep[i] {
+			continue
+		}
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+// Query returns entries for server (all servers when empty) recorded at or
+// after since, oldest first
+func (s *Store) Query(server string, since time.Time) ([]health.CheckResult, error) {
+	entries, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+	var out []health.CheckResult
+	for _, e := range entries {
+		if server != "" && e.ServerName != server {
+			continue
+		}
+		if e.Timestamp.Before(since) {
+			continue
+		}
+		out = append(out, e)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Timestamp.Before(out[j].Timestamp) })
+	return out, nil
+}
+
+// Summarize computes per-server stats, sorted by server name
+func Summarize(entries []health.CheckResult) []ServerStats {
+	byServer := map[string][]health.CheckResult{}
+	for _, e := range entries {
+		byServer[e.ServerName] = append(byServer[e.ServerName], e)
+	}
+
+	stats := make([]ServerStats, 0, len(byServer))
+	for name, es := range byServer {
+		st := ServerStats{Server: name, Checks: len(es)}
+		durations := make([]time.Duration, 0, len(es))
+		for _, e := range es {
+			durations = append(durations, e.Duration)
+			if e.Status == health.StatusHealthy {
+				st.Passed++
+			} else if !e.Timestamp.Before(st.LastFailureAt) {
+				st.LastFailure = e.Message
+				st.LastFailureAt = e.Timestamp
+			}
+			if !e.Timestamp.Before(st.LastCheckAt) {
+				st.LastCheckAt = e.Timestamp
+				st.LastStatus = string(e.Status)
+			}
+		}
+		st.PassRate = float64(st.Passed) / float64(st.Checks)
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		st.P50 = percentile(durations, 50)
+		st.P95 = percentile(durations, 95)
+		stats = append(stats, st)
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Server < stats[j].Server })
+	return stats
+}
+
+// percentile uses the nearest-rank method on sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// ParseSince parses a lookback such as "7d", "12h" or "30m". Days are
+// accepted in addition to time.ParseDuration units.
+func ParseSince(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64)
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
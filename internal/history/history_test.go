@@ -0,0 +1,88 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mseep/internal/health"
+)
+
+func TestAppendPrunes(t *testing.T) {
+	s := &Store{Path: filepath.Join(t.TempDir(), "history.jsonl"), MaxAge: 24 * time.Hour, MaxEntries: 2}
+	now := time.Now()
+
+	results := []health.CheckResult{
+		{ServerName: "a", Status: health.StatusHealthy, Timestamp: now.Add(-48 * time.Hour)},
+		{ServerName: "a", Status: health.StatusHealthy, Timestamp: now.Add(-3 * time.Minute)},
+		{ServerName: "a", Status: health.StatusUnhealthy, Timestamp: now.Add(-2 * time.Minute)},
+		{ServerName: "a", Status: health.StatusHealthy, Timestamp: now.Add(-1 * time.Minute)},
+		{ServerName: "b", Status: health.StatusHealthy, Timestamp: now},
+	}
+	if err := s.Append(results); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	got, err := s.Query("", time.Time{})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("kept %d entries, want 3: %+v", len(got), got)
+	}
+	if got[0].Status != health.StatusUnhealthy || got[2].ServerName != "b" {
+		t.Errorf("unexpected entries kept: %+v", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Now()
+	var entries []health.CheckResult
+	for i := 1; i <= 20; i++ {
+		status := health.StatusHealthy
+		msg := ""
+		if i == 5 || i == 10 {
+			status = health.StatusTimeout
+			msg = "timed out"
+		}
+		entries = append(entries, health.CheckResult{
+			ServerName: "db",
+			Status:     status,
+			Message:    msg,
+			Duration:   time.Duration(i) * 10 * time.Millisecond,
+			Timestamp:  now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	stats := Summarize(entries)
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 server, got %d", len(stats))
+	}
+	st := stats[0]
+	if st.Checks != 20 || st.Passed != 18 || st.PassRate != 0.9 {
+		t.Errorf("checks/passed/rate = %d/%d/%v", st.Checks, st.Passed, st.PassRate)
+	}
+	if st.P50 != 100*time.Millisecond || st.P95 != 190*time.Millisecond {
+		t.Errorf("p50/p95 = %v/%v, want 100ms/190ms", st.P50, st.P95)
+	}
+	if !st.LastFailureAt.Equal(now.Add(10*time.Minute)) || st.LastFailure != "timed out" {
+		t.Errorf("last failure = %v %q", st.LastFailureAt, st.LastFailure)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	tests := map[string]time.Duration{
+		"7d":   7 * 24 * time.Hour,
+		"12h":  12 * time.Hour,
+		"1.5d": 36 * time.Hour,
+	}
+	for in, want := range tests {
+		got, err := ParseSince(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSince(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseSince("soon"); err == nil {
+		t.Error("expected error for invalid duration")
+	}
+}
@@ -0,0 +1,123 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"mseep/internal/config"
+	"mseep/internal/health"
+	"mseep/internal/mcp"
+)
+
+// Inventory is the set of tools, prompts and resources a server exposes
+type Inventory struct {
+	Server          string             `json:"server"`
+	ServerInfo      mcp.Implementation `json:"serverInfo"`
+	ProtocolVersion string             `json:"protocolVersion"`
+	Tools           []mcp.Tool         `json:"tools"`
+	Prompts         []mcp.Prompt       `json:"prompts"`
+	Resources       []mcp.Resource     `json:"resources"`
+	FetchedAt       time.Time          `json:"fetchedAt"`
+}
+
+// Fetch launches the server through the health manager and lists its
+// capabilities. Only capabilities the server advertised are queried.
+func Fetch(ctx context.Context, mgr *health.Manager, server config.Server) (*Inventory, error) {
+	sess, err := mgr.Open(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	defer sess.Close()
+
+	info := sess.Client.Server
+	inv := &Inventory{
+		Server:          server.Name,
+		ServerInfo:      info.ServerInfo,
+		ProtocolVersion: info.ProtocolVersion,
+		Tools:           []mcp.Tool{},
+		Prompts:         []mcp.Prompt{},
+		Resources:       []mcp.Resource{},
+	}
+
+	if info.HasCapability("tools") {
+		if inv.Tools, err = sess.Client.ListTools(ctx); err != nil {
+			return nil, fmt.Errorf("listing tools: %w", err)
+		}
+	}
+	if info.HasCapability("prompts") {
+		if inv.Prompts, err = sess.Client.ListPrompts(ctx); err != nil {
+			return nil, fmt.Errorf("listing prompts: %w", err)
+		}
+	}
+	if info.HasCapability("resources") {
+		if inv.Resources, err = sess.Client.ListResources(ctx); err != nil {
+			return nil, fmt.Errorf("listing resources: %w", err)
+		}
+	}
+
+	inv.FetchedAt = time.Now()
+	return inv, nil
+}
+
+// CachePath returns where a server's inventory is cached
+func CachePath(server string) (string, error) {
+	dir, err := config.EnsureDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "inventory", fileName(server)+".json"), nil
+}
+
+// fileName makes a server name safe to use as a file name
+func fileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// Load reads a cached inventory. It returns nil without error when the
+// server has never been inspected.
+func Load(server string) (*Inventory, error) {
+	p, err := CachePath(server)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var inv Inventory
+	if err := json.Unmarshal(b, &inv); err != nil {
+		return nil, fmt.Errorf("invalid inventory cache %s: %w", p, err)
+	}
+	return &inv, nil
+}
+
+// Save writes an inventory to the cache
+func Save(inv *Inventory) error {
+	p, err := CachePath(inv.Server)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(inv, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, b, 0o644)
+}
@@ -0,0 +1,179 @@
+package launchenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// MinimalPath is the PATH macOS gives apps launched from Finder or the Dock
+const MinimalPath = "/usr/bin:/bin:/usr/sbin:/sbin"
+
+// Clients lists the clients whose launch environment can be simulated
+var Clients = []string{"claude", "cursor", "vscode", "cline", "warp"}
+
+// Env is the environment a client starts stdio servers with, before the
+// server's own env is merged in
+type Env struct {
+	Client string
+	// Name is a display name such as "Claude Desktop"
+	Name string
+	// Minimal is set when the client does not load the user's shell
+	// profile, so PATH additions from .zshrc and friends are missing
+	Minimal bool
+	Vars    []string
+	// Keep lists the variables passed through to servers; nil passes all
+	Keep []string
+	// WorkDir is the servers' working directory; empty inherits mseep's
+	WorkDir string
+}
+
+// sdkDefaultVars are the variables the MCP TypeScript SDK's stdio client
+// passes to servers, which Claude Desktop inherits
+func sdkDefaultVars() []string {
+	if runtime.GOOS == "windows" {
+		return []string{"APPDATA", "HOMEDRIVE", "HOMEPATH", "LOCALAPPDATA", "PATH", "PROCESSOR_ARCHITECTURE", "SYSTEMDRIVE", "SYSTEMROOT", "TEMP", "USERNAME", "USERPROFILE"}
+	}
+	return []string{"HOME", "LOGNAME", "PATH", "SHELL", "TERM", "USER"}
+}
+
+// For returns the launch environment of client. An empty client means the
+// current shell, which is what mseep itself was started with.
+func For(client string) (*Env, error) {
+	switch client {
+	case "", "shell":
+		return &Env{Client: "shell", Name: "current shell", Vars: os.Environ()}, nil
+	case "claude":
+		env := &Env{Client: client, Name: "Claude Desktop", Vars: os.Environ(), Keep: sdkDefaultVars()}
+		// Claude Desktop is a GUI app; on macOS it inherits launchd's
+		// environment rather than a login shell's and runs from /
+		if runtime.GOOS == "darwin" {
+			env.Minimal = true
+			env.Vars = minimalVars()
+			env.WorkDir = "/"
+		}
+		return env, nil
+	case "cursor":
+		// VS Code based editors resolve the login shell environment at
+		// startup and pass all of it to servers
+		return &Env{Client: client, Name: "Cursor", Vars: os.Environ()}, nil
+	case "vscode":
+		return &Env{Client: client, Name: "VS Code", Vars: os.Environ()}, nil
+	case "cline":
+		return &Env{Client: client, Name: "Cline", Vars: os.Environ()}, nil
+	case "warp":
+		return &Env{Client: client, Name: "Warp", Vars: os.Environ()}, nil
+	}
+	return nil, fmt.Errorf("unknown client %q (want %s)", client, strings.Join(Clients, ", "))
+}
+
+// Environ returns the environment a server with serverEnv is started
+// with: the client's variables, filtered by Keep, overridden by serverEnv
+func (e *Env) Environ(serverEnv map[string]string) []string {
+	var keep map[string]bool
+	if e.Keep != nil {
+		keep = map[string]bool{}
+		for _, k := range e.Keep {
+			keep[k] = true
+		}
+	}
+
+	var out []string
+	for _, kv := range e.Vars {
+		k, _, _ := strings.Cut(kv, "=")
+		if _, override := serverEnv[k]; override {
+			continue
+		}
+		if keep != nil && !keep[k] {
+			continue
+		}
+		out = append(out, kv)
+	}
+	keys := make([]string, 0, len(serverEnv))
+	for k := range serverEnv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		out = append(out, k+"="+serverEnv[k])
+	}
+	return out
+}
+
+// ForServer returns a copy of e with serverEnv merged in, so LookPath
+// sees a PATH the server's env overrides
+func (e *Env) ForServer(serverEnv map[string]string) *Env {
+	c := *e
+	c.Vars = e.Environ(serverEnv)
+	c.Keep = nil
+	return &c
+}
+
+// minimalVars keeps the identity variables launchd provides and replaces
+// PATH with the system default
+func minimalVars() []string {
+	vars := []string{"PATH=" + MinimalPath}
+	for _, key := range []string{"HOME", "USER", "LOGNAME", "SHELL", "TMPDIR"} {
+		if v, ok := os.LookupEnv(key); ok {
+			vars = append(vars, key+"="+v)
+		}
+	}
+	return vars
+}
+
+// Getenv returns the value of key in the environment
+func (e *Env) Getenv(key string) string {
+	prefix := key + "="
+	for i := len(e.Vars) - 1; i >= 0; i-- {
+		if strings.HasPrefix(e.Vars[i], prefix) {
+			return e.Vars[i][len(prefix):]
+		}
+	}
+	return ""
+}
+
+// LookPath resolves file like exec.LookPath, but against this
+// environment's PATH instead of mseep's own
+func (e *Env) LookPath(file string) (string, error) {
+	if strings.ContainsRune(file, filepath.Separator) || strings.ContainsRune(file, '/') {
+		if isExecutable(file) {
+			return file, nil
+		}
+		return "", fmt.Errorf("%s: %w", file, os.ErrNotExist)
+	}
+
+	exts := []string{""}
+	if runtime.GOOS == "windows" {
+		exts = append(exts, filepath.SplitList(strings.ToLower(e.Getenv("PATHEXT")))...)
+	}
+	for _, dir := range filepath.SplitList(e.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		for _, ext := range exts {
+			p := filepath.Join(dir, file+ext)
+			if isExecutable(p) {
+				return p, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%s not found on %s PATH: %w", file, e.Name, ErrNotFound)
+}
+
+// ErrNotFound is returned by LookPath when the command is not on PATH
+var ErrNotFound = errors.New("executable not found")
+
+func isExecutable(path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil || fi.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return fi.Mode()&0o111 != 0
+}
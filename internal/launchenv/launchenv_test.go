@@ -0,0 +1,51 @@
+package launchenv
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestEnviron(t *testing.T) {
+	e := &Env{
+		Vars: []string{"PATH=/usr/bin", "HOME=/home/u", "SECRET=x"},
+		Keep: []string{"PATH", "HOME"},
+	}
+	got := e.Environ(map[string]string{"HOME": "/srv", "TOKEN": "t"})
+	want := []string{"PATH=/usr/bin", "HOME=/srv", "TOKEN=t"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Environ() = %v, want %v", got, want)
+	}
+
+	e.Keep = nil
+	if got := e.Environ(nil); !slices.Contains(got, "SECRET=x") {
+		t.Errorf("nil Keep should pass every variable, got %v", got)
+	}
+}
+
+func TestLookPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("relies on the executable bit")
+	}
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "srv")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "data"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := (&Env{Name: "test", Vars: []string{"PATH=" + MinimalPath}}).ForServer(map[string]string{"PATH": dir})
+	if got, err := e.LookPath("srv"); err != nil || got != bin {
+		t.Errorf("LookPath(srv) = %q, %v; want %q", got, err, bin)
+	}
+	if _, err := e.LookPath("data"); err == nil {
+		t.Error("LookPath should skip files that are not executable")
+	}
+	if _, err := (&Env{Name: "test", Vars: []string{"PATH=" + MinimalPath}}).LookPath("srv"); err == nil {
+		t.Error("LookPath should not see directories outside PATH")
+	}
+}
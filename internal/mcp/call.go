@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ContentBlock is one item of a tool result's content
+type ContentBlock struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// ToolResult is the result of tools/call
+type ToolResult struct {
+	Content           []ContentBlock  `json:"content"`
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
+	IsError           bool            `json:"isError,omitempty"`
+	// Raw is the undecoded result, for assertions on fields not modelled here
+	Raw json.RawMessage `json:"-"`
+}
+
+// Text joins the text content blocks of the result
+func (r *ToolResult) Text() string {
+	var parts []string
+	for _, c := range r.Content {
+		if c.Type == "text" {
+			parts = append(parts, c.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// CallTool invokes a tool. A result with IsError set is returned without
+// an error; errors are reserved for failed requests.
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]interface{}) (*ToolResult, error) {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	var raw json.RawMessage
+	if err := c.Call(ctx, "tools/call", map[string]interface{}{"name": name, "arguments": args}, &raw); err != nil {
+		return nil, err
+	}
+	var res ToolResult
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return nil, &ProtocolError{Method: "tools/call", Reason: fmt.Sprintf("invalid result: %v", err)}
+	}
+	res.Raw = raw
+	return &res, nil
+}
@@ -0,0 +1,419 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HTTPError is a non-success HTTP status returned by a remote server
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("HTTP %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Body != "" {
+		msg += ": " + truncate(strings.TrimSpace(e.Body), 120)
+	}
+	return msg
+}
+
+func httpError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return &HTTPError{StatusCode: resp.StatusCode, Body: string(body)}
+}
+
+// closeTimeout bounds the DELETE that ends an HTTP session
+const closeTimeout = 5 * time.Second
+
+// HTTPTransport implements the streamable HTTP transport: each message is
+// POSTed to a single endpoint and answered with JSON or an SSE stream.
+type HTTPTransport struct {
+	url       string
+	client    *http.Client
+	headers   http.Header
+	sessionID string
+	version   string
+}
+
+// NewHTTPTransport creates a streamable HTTP transport. headers are added
+// to every request (e.g. Authorization).
+func NewHTTPTransport(endpoint string, client *http.Client, headers http.Header) *HTTPTransport {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPTransport{url: endpoint, client: client, headers: headers}
+}
+
+func (t *HTTPTransport) newRequest(ctx context.Context, method string, body []byte) (*http.Request, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, t.url, r)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range t.headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if t.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", t.sessionID)
+	}
+	if t.version != "" {
+		req.Header.Set("MCP-Protocol-Version", t.version)
+	}
+	return req, nil
+}
+
+func (t *HTTPTransport) post(ctx context.Context, msg *Request) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := t.newRequest(ctx, http.MethodPost, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, httpError(resp)
+	}
+	return resp, nil
+}
+
+// Call POSTs req and reads the response from either a JSON body or an SSE
+// stream. The session ID and negotiated protocol version from initialize
+// are remembered for later requests.
+func (t *HTTPTransport) Call(ctx context.Context, req *Request) (*Response, error) {
+	if req.ID == nil {
+		return nil, fmt.Errorf("call %s: request has no id", req.Method)
+	}
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" && req.Method == "initialize" {
+		t.sessionID = id
+	}
+
+	want := strconv.FormatInt(*req.ID, 10)
+	var msg *Response
+	mediaType := strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0])
+	switch mediaType {
+	case "application/json":
+		var r Response
+		if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+			return nil, &ProtocolError{Method: req.Method, Reason: fmt.Sprintf("invalid JSON response: %v", err)}
+		}
+		msg = &r
+	case "text/event-stream":
+		err := readSSE(resp.Body, func(event, data string) (bool, error) {
+			if event != "" && event != "message" {
+				return true, nil
+			}
+			var r Response
+			if err := json.Unmarshal([]byte(data), &r); err != nil {
+				return false, &ProtocolError{Method: req.Method, Reason: fmt.Sprintf("invalid SSE message: %v", err)}
+			}
+			if r.Method != "" || string(r.ID) != want {
+				return true, nil
+			}
+			msg = &r
+			return false, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if msg == nil {
+			return nil, ErrClosed
+		}
+	default:
+		return nil, &ProtocolError{Method: req.Method, Reason: fmt.Sprintf("unexpected content type %q", resp.Header.Get("Content-Type"))}
+	}
+
+	if req.Method == "initialize" && msg.Error == nil {
+		var res struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(msg.Result, &res)
+		t.version = res.ProtocolVersion
+	}
+	return msg, nil
+}
+
+// Notify POSTs a notification, which servers acknowledge with 202 Accepted
+func (t *HTTPTransport) Notify(ctx context.Context, req *Request) error {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Close ends the session with a DELETE when the server issued a session ID.
+// Servers may refuse with 405, which is not an error.
+func (t *HTTPTransport) Close() error {
+	if t.sessionID == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	req, err := t.newRequest(ctx, http.MethodDelete, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// SSETransport implements the legacy HTTP+SSE transport: a long-lived GET
+// stream announces a POST endpoint, and responses arrive on the stream.
+type SSETransport struct {
+	endpoint string
+	client   *http.Client
+	headers  http.Header
+	cancel   context.CancelFunc
+	msgs     chan *Response
+	errc     chan error
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewSSETransport opens the event stream at streamURL and waits for the
+// server to announce its message endpoint
+func NewSSETransport(ctx context.Context, streamURL string, client *http.Client, headers http.Header) (*SSETransport, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	base, err := url.Parse(streamURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stream must outlive the handshake, so Close cancels it rather than ctx
+	streamCtx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodGet, streamURL, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	for k, vs := range headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	type opened struct {
+		resp *http.Response
+		err  error
+	}
+	done := make(chan opened, 1)
+	go func() {
+		resp, err := client.Do(req)
+		done <- opened{resp, err}
+	}()
+
+	var resp *http.Response
+	select {
+	case <-ctx.Done():
+		cancel()
+		return nil, ctx.Err()
+	case o := <-done:
+		if o.err != nil {
+			cancel()
+			return nil, o.err
+		}
+		resp = o.resp
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		cancel()
+		return nil, httpError(resp)
+	}
+
+	t := &SSETransport{
+		client:  client,
+		headers: headers,
+		cancel:  cancel,
+		msgs:    make(chan *Response),
+		errc:    make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+	endpoint := make(chan string, 1)
+	go t.readLoop(resp.Body, endpoint)
+
+	select {
+	case <-ctx.Done():
+		t.Close()
+		return nil, ctx.Err()
+	case err := <-t.errc:
+		t.Close()
+		return nil, err
+	case ep := <-endpoint:
+		ref, err := url.Parse(ep)
+		if err != nil {
+			t.Close()
+			return nil, &ProtocolError{Reason: fmt.Sprintf("invalid endpoint event %q", ep)}
+		}
+		t.endpoint = base.ResolveReference(ref).String()
+	}
+	return t, nil
+}
+
+func (t *SSETransport) readLoop(body io.ReadCloser, endpoint chan<- string) {
+	defer body.Close()
+	sentEndpoint := false
+	err := readSSE(body, func(event, data string) (bool, error) {
+		switch event {
+		case "endpoint":
+			if !sentEndpoint {
+				sentEndpoint = true
+				endpoint <- strings.TrimSpace(data)
+			}
+		case "", "message":
+			var r Response
+			if err := json.Unmarshal([]byte(data), &r); err != nil {
+				return false, &ProtocolError{Reason: fmt.Sprintf("invalid SSE message: %v", err)}
+			}
+			select {
+			case t.msgs <- &r:
+			case <-t.done:
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err == nil {
+		err = ErrClosed
+	}
+	t.errc <- err
+}
+
+func (t *SSETransport) post(ctx context.Context, msg *Request) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, vs := range t.headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return httpError(resp)
+	}
+	return nil
+}
+
+// Call POSTs req to the message endpoint and waits for the matching
+// response on the event stream
+func (t *SSETransport) Call(ctx context.Context, req *Request) (*Response, error) {
+	if req.ID == nil {
+		return nil, fmt.Errorf("call %s: request has no id", req.Method)
+	}
+	if err := t.post(ctx, req); err != nil {
+		return nil, err
+	}
+	want := strconv.FormatInt(*req.ID, 10)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-t.errc:
+			t.errc <- err
+			return nil, err
+		case msg := <-t.msgs:
+			if msg.Method == "" && string(msg.ID) == want {
+				return msg, nil
+			}
+		}
+	}
+}
+
+// Notify POSTs a notification to the message endpoint
+func (t *SSETransport) Notify(ctx context.Context, req *Request) error {
+	return t.post(ctx, req)
+}
+
+// Close drops the event stream
+func (t *SSETransport) Close() error {
+	t.once.Do(func() {
+		t.cancel()
+		close(t.done)
+	})
+	return nil
+}
+
+// readSSE parses a server-sent event stream, calling fn for each event
+// until fn returns false or the stream ends
+func readSSE(r io.Reader, fn func(event, data string) (bool, error)) error {
+	br := bufio.NewReader(r)
+	var event string
+	var data []string
+	for {
+		line, err := br.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "" && err == nil:
+			if len(data) > 0 {
+				more, ferr := fn(event, strings.Join(data, "\n"))
+				if ferr != nil || !more {
+					return ferr
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// comment / keep-alive
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
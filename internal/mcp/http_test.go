@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const initResult = `{"protocolVersion":"2025-06-18","capabilities":{},"serverInfo":{"name":"remote","version":"2.0"}}`
+
+func TestHTTPTransport(t *testing.T) {
+	for _, stream := range []bool{false, true} {
+		t.Run(fmt.Sprintf("stream=%v", stream), func(t *testing.T) {
+			var gotSession, gotVersion, gotAuth string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&req)
+
+				switch req["method"] {
+				case "initialize":
+					gotAuth = r.Header.Get("Authorization")
+					w.Header().Set("Mcp-Session-Id", "sess-1")
+					body := fmt.Sprintf(`{"jsonrpc":"2.0","id":%v,"result":%s}`, req["id"], initResult)
+					if stream {
+						w.Header().Set("Content-Type", "text/event-stream")
+						fmt.Fprintf(w, ": keep-alive\n\nevent: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/message\"}\n\ndata: %s\n\n", body)
+					} else {
+						w.Header().Set("Content-Type", "application/json")
+						fmt.Fprint(w, body)
+					}
+				case "notifications/initialized":
+					gotSession = r.Header.Get("Mcp-Session-Id")
+					gotVersion = r.Header.Get("MCP-Protocol-Version")
+					w.WriteHeader(http.StatusAccepted)
+				}
+			}))
+			defer srv.Close()
+
+			headers := http.Header{"Authorization": {"Bearer secret"}}
+			c := NewClient(NewHTTPTransport(srv.URL, srv.Client(), headers))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			res, err := c.Initialize(ctx)
+			if err != nil {
+				t.Fatalf("Initialize() error = %v", err)
+			}
+			if res.ServerInfo.Name != "remote" {
+				t.Errorf("serverInfo.name = %q, want remote", res.ServerInfo.Name)
+			}
+			if gotAuth != "Bearer secret" {
+				t.Errorf("Authorization = %q", gotAuth)
+			}
+			if gotSession != "sess-1" {
+				t.Errorf("session header on follow-up = %q, want sess-1", gotSession)
+			}
+			if gotVersion != "2025-06-18" {
+				t.Errorf("protocol version header = %q", gotVersion)
+			}
+		})
+	}
+}
+
+func TestHTTPTransportStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "missing token", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	c := NewClient(NewHTTPTransport(srv.URL, srv.Client(), nil))
+	_, err := c.Initialize(context.Background())
+
+	he, ok := err.(*HTTPError)
+	if !ok || he.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("error = %v, want HTTP 401", err)
+	}
+	if IsProtocolError(err) {
+		t.Error("HTTP status errors should not count as protocol errors")
+	}
+}
+
+func TestSSETransport(t *testing.T) {
+	messages := make(chan string, 4)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sse", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: endpoint\ndata: /messages?session=abc\n\n")
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case m := <-messages:
+				fmt.Fprintf(w, "event: message\ndata: %s\n\n", m)
+				w.(http.Flusher).Flush()
+			}
+		}
+	})
+	mux.HandleFunc("/messages", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("session") != "abc" {
+			http.Error(w, "bad session", http.StatusBadRequest)
+			return
+		}
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["method"] == "initialize" {
+			messages <- fmt.Sprintf(`{"jsonrpc":"2.0","id":%v,"result":%s}`, req["id"], initResult)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	tr, err := NewSSETransport(ctx, srv.URL+"/sse", srv.Client(), nil)
+	if err != nil {
+		t.Fatalf("NewSSETransport() error = %v", err)
+	}
+	c := NewClient(tr)
+	defer c.Close()
+
+	res, err := c.Initialize(ctx)
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if res.ServerInfo.Version != "2.0" {
+		t.Errorf("serverInfo.version = %q, want 2.0", res.ServerInfo.Version)
+	}
+}
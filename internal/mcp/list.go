@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// maxPages bounds pagination so a server returning the same cursor forever
+// cannot hang a listing
+const maxPages = 100
+
+// Tool is a tool definition from tools/list
+type Tool struct {
+	Name        string          `json:"name"`
+	Title       string          `json:"title,omitempty"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"inputSchema,omitempty"`
+	Annotations json.RawMessage `json:"annotations,omitempty"`
+}
+
+// Prompt is a prompt template from prompts/list
+type Prompt struct {
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptArgument describes one argument of a prompt
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Resource is a resource from resources/list
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// listAll calls method repeatedly, following nextCursor, and passes each
+// page's raw result to collect
+func (c *Client) listAll(ctx context.Context, method string, collect func(json.RawMessage) error) error {
+	cursor := ""
+	for page := 0; page < maxPages; page++ {
+		var params interface{}
+		if cursor != "" {
+			params = map[string]string{"cursor": cursor}
+		}
+
+		var raw json.RawMessage
+		if err := c.Call(ctx, method, params, &raw); err != nil {
+			return err
+		}
+		if err := collect(raw); err != nil {
+			return &ProtocolError{Method: method, Reason: fmt.Sprintf("invalid result: %v", err)}
+		}
+
+		var next struct {
+			NextCursor string `json:"nextCursor"`
+		}
+		json.Unmarshal(raw, &next)
+		if next.NextCursor == "" {
+			return nil
+		}
+		if next.NextCursor == cursor {
+			return &ProtocolError{Method: method, Reason: "server repeated the same cursor"}
+		}
+		cursor = next.NextCursor
+	}
+	return &ProtocolError{Method: method, Reason: fmt.Sprintf("more than %d pages", maxPages)}
+}
+
+// ListTools returns every tool the server exposes
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var all []Tool
+	err := c.listAll(ctx, "tools/list", func(raw json.RawMessage) error {
+		var page struct {
+			Tools []Tool `json:"tools"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		all = append(all, page.Tools...)
+		return nil
+	})
+	return all, err
+}
+
+// ListPrompts returns every prompt the server exposes
+func (c *Client) ListPrompts(ctx context.Context) ([]Prompt, error) {
+	var all []Prompt
+	err := c.listAll(ctx, "prompts/list", func(raw json.RawMessage) error {
+		var page struct {
+			Prompts []Prompt `json:"prompts"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		all = append(all, page.Prompts...)
+		return nil
+	})
+	return all, err
+}
+
+// ListResources returns every resource the server exposes
+func (c *Client) ListResources(ctx context.Context) ([]Resource, error) {
+	var all []Resource
+	err := c.listAll(ctx, "resources/list", func(raw json.RawMessage) error {
+		var page struct {
+			Resources []Resource `json:"resources"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		all = append(all, page.Resources...)
+		return nil
+	})
+	return all, err
+}
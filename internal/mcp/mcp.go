@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ProtocolVersion is the MCP protocol revision mseep requests during initialize
+const ProtocolVersion = "2025-06-18"
+
+// ClientName and ClientVersion identify mseep to servers
+const (
+	ClientName    = "mseep"
+	ClientVersion = "0.0.1"
+)
+
+// Request is a JSON-RPC 2.0 request or notification (ID == nil)
+type Request struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      *int64      `json:"id,omitempty"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC 2.0 message received from a server.
+// Server-initiated requests and notifications carry Method instead of a result.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is a JSON-RPC error object returned by a server
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// ProtocolError reports a server that responded, but not like an MCP server
+type ProtocolError struct {
+	Method string
+	Reason string
+}
+
+func (e *ProtocolError) Error() string {
+	if e.Method == "" {
+		return "protocol error: " + e.Reason
+	}
+	return fmt.Sprintf("protocol error in %s: %s", e.Method, e.Reason)
+}
+
+// IsProtocolError reports whether err is a protocol-level failure
+// (malformed messages or JSON-RPC errors) rather than a transport failure.
+func IsProtocolError(err error) bool {
+	var pe *ProtocolError
+	var re *RPCError
+	return errors.As(err, &pe) || errors.As(err, &re)
+}
+
+// Transport carries JSON-RPC messages between mseep and a server.
+// Calls are issued sequentially; transports need not support concurrent use.
+type Transport interface {
+	// Call sends a request and waits for the response with the same ID
+	Call(ctx context.Context, req *Request) (*Response, error)
+	// Notify sends a notification, which has no response
+	Notify(ctx context.Context, req *Request) error
+	Close() error
+}
+
+// Implementation names a client or server and its version
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// InitializeResult is the server's answer to initialize
+type InitializeResult struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities"`
+	ServerInfo      Implementation         `json:"serverInfo"`
+	Instructions    string                 `json:"instructions,omitempty"`
+}
+
+// HasCapability reports whether the server advertised the named capability
+func (r *InitializeResult) HasCapability(name string) bool {
+	if r == nil || r.Capabilities == nil {
+		return false
+	}
+	_, ok := r.Capabilities[name]
+	return ok
+}
+
+// Client speaks MCP over a Transport
+type Client struct {
+	transport Transport
+	nextID    int64
+	// Server holds the initialize result once the handshake has completed
+	Server *InitializeResult
+}
+
+// NewClient creates a client over the given transport
+func NewClient(t Transport) *Client {
+	return &Client{transport: t}
+}
+
+// Call issues a request and decodes its result into out (which may be nil)
+func (c *Client) Call(ctx context.Context, method string, params, out interface{}) error {
+	id := atomic.AddInt64(&c.nextID, 1)
+	resp, err := c.transport.Call(ctx, &Request{JSONRPC: "2.0", ID: &id, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if resp.JSONRPC != "2.0" {
+		return &ProtocolError{Method: method, Reason: fmt.Sprintf("unexpected jsonrpc version %q", resp.JSONRPC)}
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if resp.Result == nil {
+		return &ProtocolError{Method: method, Reason: "response has neither result nor error"}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, out); err != nil {
+		return &ProtocolError{Method: method, Reason: fmt.Sprintf("invalid result: %v", err)}
+	}
+	return nil
+}
+
+// Notify sends a notification
+func (c *Client) Notify(ctx context.Context, method string, params interface{}) error {
+	return c.transport.Notify(ctx, &Request{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// Initialize performs the MCP handshake: initialize followed by
+// notifications/initialized. The result is validated and stored in c.Server.
+func (c *Client) Initialize(ctx context.Context) (*InitializeResult, error) {
+	params := map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      Implementation{Name: ClientName, Version: ClientVersion},
+	}
+
+	var res InitializeResult
+	if err := c.Call(ctx, "initialize", params, &res); err != nil {
+		return nil, err
+	}
+	if res.ProtocolVersion == "" {
+		return nil, &ProtocolError{Method: "initialize", Reason: "missing protocolVersion"}
+	}
+	if res.ServerInfo.Name == "" {
+		return nil, &ProtocolError{Method: "initialize", Reason: "missing serverInfo.name"}
+	}
+
+	if err := c.Notify(ctx, "notifications/initialized", nil); err != nil {
+		return nil, err
+	}
+
+	c.Server = &res
+	return &res, nil
+}
+
+// Close closes the underlying transport
+func (c *Client) Close() error {
+	return c.transport.Close()
+}
@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// fakeServer answers requests read from the client with handle's result.
+// Returning nil from handle closes the server's stdout.
+func fakeServer(t *testing.T, handle func(req map[string]interface{}) []string) *Client {
+	t.Helper()
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+
+	go func() {
+		defer serverW.Close()
+		sc := bufio.NewScanner(serverR)
+		for sc.Scan() {
+			var req map[string]interface{}
+			if err := json.Unmarshal(sc.Bytes(), &req); err != nil {
+				t.Errorf("client sent invalid JSON: %v", err)
+				return
+			}
+			lines := handle(req)
+			if lines == nil {
+				return
+			}
+			for _, l := range lines {
+				serverW.Write([]byte(l + "\n"))
+			}
+		}
+	}()
+
+	c := NewClient(NewStdioTransport(clientR, clientW))
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestInitialize(t *testing.T) {
+	initialized := make(chan struct{})
+	c := fakeServer(t, func(req map[string]interface{}) []string {
+		switch req["method"] {
+		case "initialize":
+			return []string{
+				`{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"info"}}`,
+				`{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-06-18","capabilities":{"tools":{}},"serverInfo":{"name":"fake","version":"1.2.3"}}}`,
+			}
+		case "notifications/initialized":
+			close(initialized)
+		}
+		return []string{}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	res, err := c.Initialize(ctx)
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if res.ServerInfo.Name != "fake" || res.ServerInfo.Version != "1.2.3" {
+		t.Errorf("serverInfo = %+v", res.ServerInfo)
+	}
+	if !res.HasCapability("tools") {
+		t.Error("expected tools capability")
+	}
+
+	select {
+	case <-initialized:
+	case <-time.After(time.Second):
+		t.Error("notifications/initialized was not sent")
+	}
+}
+
+func TestInitializeErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     []string
+		wantProto bool
+		wantErr   error
+	}{
+		{
+			name:      "non-JSON output",
+			reply:     []string{"Traceback (most recent call last):"},
+			wantProto: true,
+		},
+		{
+			name:      "missing server info",
+			reply:     []string{`{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-06-18"}}`},
+			wantProto: true,
+		},
+		{
+			name:      "rpc error",
+			reply:     []string{`{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"bad"}}`},
+			wantProto: true,
+		},
+		{
+			name:    "server exits",
+			reply:   nil,
+			wantErr: ErrClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fakeServer(t, func(req map[string]interface{}) []string { return tt.reply })
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			_, err := c.Initialize(ctx)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if IsProtocolError(err) != tt.wantProto {
+				t.Errorf("IsProtocolError(%v) = %v, want %v", err, !tt.wantProto, tt.wantProto)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInitializeTimeout(t *testing.T) {
+	c := fakeServer(t, func(req map[string]interface{}) []string { return []string{} })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.Initialize(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestListToolsPagination(t *testing.T) {
+	c := fakeServer(t, func(req map[string]interface{}) []string {
+		id := req["id"]
+		if id == nil {
+			return []string{}
+		}
+		params, _ := req["params"].(map[string]interface{})
+		if params["cursor"] == "page2" {
+			return []string{`{"jsonrpc":"2.0","id":` + jsonString(id) + `,"result":{"tools":[{"name":"b"}]}}`}
+		}
+		return []string{`{"jsonrpc":"2.0","id":` + jsonString(id) + `,"result":{"tools":[{"name":"a"}],"nextCursor":"page2"}}`}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	if len(tools) != 2 || tools[0].Name != "a" || tools[1].Name != "b" {
+		t.Errorf("tools = %+v, want [a b]", tools)
+	}
+}
+
+func jsonString(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func TestCallTool(t *testing.T) {
+	c := fakeServer(t, func(req map[string]interface{}) []string {
+		id := req["id"]
+		if id == nil {
+			return []string{}
+		}
+		params, _ := req["params"].(map[string]interface{})
+		if params["name"] != "echo" {
+			return []string{`{"jsonrpc":"2.0","id":` + jsonString(id) + `,"error":{"code":-32602,"message":"unknown tool"}}`}
+		}
+		args, _ := params["arguments"].(map[string]interface{})
+		return []string{`{"jsonrpc":"2.0","id":` + jsonString(id) + `,"result":{"content":[{"type":"text","text":` + jsonString(args["text"]) + `}]}}`}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	res, err := c.CallTool(ctx, "echo", map[string]interface{}{"text": "hi"})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if res.IsError || res.Text() != "hi" {
+		t.Errorf("result = %+v, want text hi", res)
+	}
+	if _, err := c.CallTool(ctx, "nope", nil); err == nil {
+		t.Error("expected error for unknown tool")
+	}
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// ErrClosed is returned when the server closes its side of the connection
+var ErrClosed = errors.New("server closed the connection")
+
+// StdioTransport exchanges newline-delimited JSON-RPC messages over a
+// server's stdout (r) and stdin (w).
+type StdioTransport struct {
+	w       io.WriteCloser
+	msgs    chan *Response
+	errc    chan error
+	done    chan struct{}
+	once    sync.Once
+	writeMu sync.Mutex
+}
+
+// NewStdioTransport starts reading messages from r in the background
+func NewStdioTransport(r io.Reader, w io.WriteCloser) *StdioTransport {
+	t := &StdioTransport{
+		w:    w,
+		msgs: make(chan *Response),
+		errc: make(chan error, 1),
+		done: make(chan struct{}),
+	}
+	go t.readLoop(r)
+	return t
+}
+
+func (t *StdioTransport) readLoop(r io.Reader) {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			var msg Response
+			if jerr := json.Unmarshal(line, &msg); jerr != nil {
+				t.errc <- &ProtocolError{Reason: fmt.Sprintf("server wrote non-JSON to stdout: %q", truncate(string(line), 120))}
+				return
+			}
+			select {
+			case t.msgs <- &msg:
+			case <-t.done:
+				return
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				err = ErrClosed
+			}
+			t.errc <- err
+			return
+		}
+	}
+}
+
+func (t *StdioTransport) write(req interface{}) error {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err = t.w.Write(append(b, '\n'))
+	return err
+}
+
+// Call writes req and waits for the matching response. Server notifications
+// are skipped; server requests are answered so the server is not left waiting.
+func (t *StdioTransport) Call(ctx context.Context, req *Request) (*Response, error) {
+	if req.ID == nil {
+		return nil, fmt.Errorf("call %s: request has no id", req.Method)
+	}
+	if err := t.write(req); err != nil {
+		return nil, fmt.Errorf("write %s: %w", req.Method, err)
+	}
+	want := strconv.FormatInt(*req.ID, 10)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-t.errc:
+			// Keep the error available for subsequent calls
+			t.errc <- err
+			return nil, err
+		case msg := <-t.msgs:
+			if msg.Method != "" {
+				if len(msg.ID) > 0 {
+					t.answer(msg)
+				}
+				continue
+			}
+			if string(msg.ID) != want {
+				continue
+			}
+			return msg, nil
+		}
+	}
+}
+
+// answer replies to a server-initiated request: ping succeeds, anything else
+// is reported as unsupported.
+func (t *StdioTransport) answer(msg *Response) {
+	reply := map[string]interface{}{"jsonrpc": "2.0", "id": msg.ID}
+	if msg.Method == "ping" {
+		reply["result"] = map[string]interface{}{}
+	} else {
+		reply["error"] = RPCError{Code: -32601, Message: "method not supported by mseep: " + msg.Method}
+	}
+	_ = t.write(reply)
+}
+
+// Notify writes a notification
+func (t *StdioTransport) Notify(ctx context.Context, req *Request) error {
+	if err := t.write(req); err != nil {
+		return fmt.Errorf("write %s: %w", req.Method, err)
+	}
+	return nil
+}
+
+// Close closes the server's stdin, which signals it to shut down
+func (t *StdioTransport) Close() error {
+	t.once.Do(func() { close(t.done) })
+	return t.w.Close()
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
@@ -0,0 +1,200 @@
+package pin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"mseep/internal/config"
+	"mseep/internal/mcp"
+)
+
+// Pin records the tool definitions a user approved for a server
+type Pin struct {
+	Server     string             `json:"server"`
+	Hash       string             `json:"hash"`
+	Tools      map[string]ToolPin `json:"tools"`
+	ApprovedAt time.Time          `json:"approvedAt"`
+}
+
+// ToolPin is the approved state of one tool
+type ToolPin struct {
+	Description string `json:"description"`
+	SchemaHash  string `json:"schemaHash"`
+}
+
+// Store holds pins for all servers, keyed by server name
+type Store struct {
+	Pins map[string]*Pin `json:"pins"`
+}
+
+// Drift describes how a server's tools differ from its pin
+type Drift struct {
+	Server  string       `json:"server"`
+	Added   []string     `json:"added,omitempty"`
+	Removed []string     `json:"removed,omitempty"`
+	Changed []ToolChange `json:"changed,omitempty"`
+}
+
+// ToolChange is a pinned tool whose description or schema changed
+type ToolChange struct {
+	Name           string `json:"name"`
+	OldDescription string `json:"oldDescription"`
+	NewDescription string `json:"newDescription"`
+	SchemaChanged  bool   `json:"schemaChanged"`
+}
+
+// Empty reports whether the tools match the pin
+func (d *Drift) Empty() bool {
+	return d == nil || len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Summary describes the drift in one line, e.g. "added a; changed b"
+func (d *Drift) Summary() string {
+	var parts []string
+	if len(d.Added) > 0 {
+		parts = append(parts, "added "+strings.Join(d.Added, ", "))
+	}
+	if len(d.Removed) > 0 {
+		parts = append(parts, "removed "+strings.Join(d.Removed, ", "))
+	}
+	if len(d.Changed) > 0 {
+		names := make([]string, len(d.Changed))
+		for i, c := range d.Changed {
+			names[i] = c.Name
+		}
+		parts = append(parts, "changed "+strings.Join(names, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Path returns the location of the pin store
+func Path() (string, error) {
+	dir, err := config.EnsureDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pins.json"), nil
+}
+
+// Load reads the pin store, returning an empty store when none exists
+func Load() (*Store, error) {
+	p, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{Pins: map[string]*Pin{}}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("invalid pin store %s: %w", p, err)
+	}
+	if s.Pins == nil {
+		s.Pins = map[string]*Pin{}
+	}
+	return s, nil
+}
+
+// Save writes the pin store
+func (s *Store) Save() error {
+	p, err := Path()
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, b, 0o644)
+}
+
+// New pins the given tools for server
+func New(server string, tools []mcp.Tool) *Pin {
+	p := &Pin{
+		Server:     server,
+		Hash:       Hash(tools),
+		Tools:      make(map[string]ToolPin, len(tools)),
+		ApprovedAt: time.Now(),
+	}
+	for _, t := range tools {
+		p.Tools[t.Name] = ToolPin{Description: t.Description, SchemaHash: schemaHash(t.InputSchema)}
+	}
+	return p
+}
+
+// Hash fingerprints tool names, descriptions and input schemas independent
+// of listing order and JSON key order
+func Hash(tools []mcp.Tool) string {
+	sorted := make([]mcp.Tool, len(tools))
+	copy(sorted, tools)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	h := sha256.New()
+	for _, t := range sorted {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00", t.Name, t.Description, schemaHash(t.InputSchema))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// schemaHash hashes a JSON schema after normalizing key order
+func schemaHash(schema json.RawMessage) string {
+	var v interface{}
+	if len(schema) > 0 {
+		if err := json.Unmarshal(schema, &v); err != nil {
+			v = string(schema)
+		}
+	}
+	// encoding/json writes map keys sorted, so re-marshaling normalizes
+	b, _ := json.Marshal(v)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// Compare reports how tools differ from the pin
+func (p *Pin) Compare(tools []mcp.Tool) *Drift {
+	d := &Drift{Server: p.Server}
+	if Hash(tools) == p.Hash {
+		return d
+	}
+
+	seen := map[string]bool{}
+	for _, t := range tools {
+		seen[t.Name] = true
+		old, ok := p.Tools[t.Name]
+		if !ok {
+			d.Added = append(d.Added, t.Name)
+			continue
+		}
+		schemaChanged := old.SchemaHash != schemaHash(t.InputSchema)
+		if old.Description != t.Description || schemaChanged {
+			d.Changed = append(d.Changed, ToolChange{
+				Name:           t.Name,
+				OldDescription: old.Description,
+				NewDescription: t.Description,
+				SchemaChanged:  schemaChanged,
+			})
+		}
+	}
+	for name := range p.Tools {
+		if !seen[name] {
+			d.Removed = append(d.Removed, name)
+		}
+	}
+
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].Name < d.Changed[j].Name })
+	return d
+}
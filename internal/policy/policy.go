@@ -0,0 +1,97 @@
+package policy
+
+import (
+	"fmt"
+	"time"
+
+	"mseep/internal/config"
+	"mseep/internal/health"
+)
+
+// Defaults applied when a PolicySpec leaves a field unset
+const (
+	DefaultFailureThreshold = 3
+	DefaultWindowHours      = 24
+	DefaultCooldownHours    = 24
+)
+
+// Action is what the policy engine decided for a server
+type Action string
+
+const (
+	ActionNone    Action = "none"
+	ActionDisable Action = "disable"
+	ActionEnable  Action = "enable"
+)
+
+// Decision is the outcome of evaluating one server
+type Decision struct {
+	Server   string `json:"server"`
+	Action   Action `json:"action"`
+	Reason   string `json:"reason"`
+	Failures int    `json:"failures"`
+}
+
+// Effective fills unset fields of spec with defaults. A nil spec yields the
+// default policy with AutoDisable off.
+func Effective(spec *config.PolicySpec) config.PolicySpec {
+	var p config.PolicySpec
+	if spec != nil {
+		p = *spec
+	}
+	if p.FailureThreshold <= 0 {
+		p.FailureThreshold = DefaultFailureThreshold
+	}
+	if p.WindowHours <= 0 {
+		p.WindowHours = DefaultWindowHours
+	}
+	if p.CooldownHours <= 0 {
+		p.CooldownHours = DefaultCooldownHours
+	}
+	return p
+}
+
+// Evaluate decides whether server should be disabled or re-enabled.
+// history holds the server's recorded checks (including latest), and force
+// applies the policy even when AutoDisable is off, as 'health --fix' does.
+//
+// A failing server is disabled once failures within the window reach the
+// threshold. A server the policy disabled is re-enabled when the cooldown
+// has elapsed and the latest check passed.
+func Evaluate(server config.Server, history []health.CheckResult, latest health.CheckResult, now time.Time, force bool) Decision {
+	p := Effective(server.Policy)
+	d := Decision{Server: server.Name, Action: ActionNone}
+
+	if server.AutoDisabled != nil {
+		until := server.AutoDisabled.At.Add(time.Duration(p.CooldownHours) * time.Hour)
+		switch {
+		case now.Before(until):
+			d.Reason = fmt.Sprintf("cooling down until %s", until.Format("2006-01-02 15:04"))
+		case latest.Status != health.StatusHealthy:
+			d.Reason = "cooldown elapsed but check still failing"
+		default:
+			d.Action = ActionEnable
+			d.Reason = fmt.Sprintf("passed after %dh cooldown", p.CooldownHours)
+		}
+		return d
+	}
+
+	if !server.EnabledAnywhere() || (!p.AutoDisable && !force) || latest.Status == health.StatusHealthy {
+		return d
+	}
+
+	since := now.Add(-time.Duration(p.WindowHours) * time.Hour)
+	for _, h := range history {
+		if h.ServerName == server.Name && !h.Timestamp.Before(since) && h.Status != health.StatusHealthy {
+			d.Failures++
+		}
+	}
+
+	if d.Failures >= p.FailureThreshold {
+		d.Action = ActionDisable
+		d.Reason = fmt.Sprintf("%d failures in %dh (threshold %d): %s", d.Failures, p.WindowHours, p.FailureThreshold, latest.Message)
+	} else if d.Failures > 0 {
+		d.Reason = fmt.Sprintf("%d of %d failures in %dh", d.Failures, p.FailureThreshold, p.WindowHours)
+	}
+	return d
+}
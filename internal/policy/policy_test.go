@@ -0,0 +1,97 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"mseep/internal/config"
+	"mseep/internal/health"
+)
+
+func failures(name string, now time.Time, ages ...time.Duration) []health.CheckResult {
+	var out []health.CheckResult
+	for _, age := range ages {
+		out = append(out, health.CheckResult{ServerName: name, Status: health.StatusUnhealthy, Timestamp: now.Add(-age)})
+	}
+	return out
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Now()
+	failing := health.CheckResult{ServerName: "db", Status: health.StatusUnhealthy, Message: "exit status 1"}
+	passing := health.CheckResult{ServerName: "db", Status: health.StatusHealthy}
+	auto := &config.PolicySpec{AutoDisable: true, FailureThreshold: 2, WindowHours: 1, CooldownHours: 2}
+
+	tests := []struct {
+		name    string
+		server  config.Server
+		history []health.CheckResult
+		latest  health.CheckResult
+		force   bool
+		want    Action
+	}{
+		{
+			name:    "below threshold",
+			server:  config.Server{Name: "db", Enabled: true, Policy: auto},
+			history: failures("db", now, 0),
+			latest:  failing,
+			want:    ActionNone,
+		},
+		{
+			name:    "threshold crossed within window",
+			server:  config.Server{Name: "db", Enabled: true, Policy: auto},
+			history: failures("db", now, 0, 30*time.Minute),
+			latest:  failing,
+			want:    ActionDisable,
+		},
+		{
+			name:    "old failures outside window",
+			server:  config.Server{Name: "db", Enabled: true, Policy: auto},
+			history: failures("db", now, 0, 2*time.Hour),
+			latest:  failing,
+			want:    ActionNone,
+		},
+		{
+			name:    "auto-disable off without force",
+			server:  config.Server{Name: "db", Enabled: true},
+			history: failures("db", now, 0, time.Minute, 2*time.Minute),
+			latest:  failing,
+			want:    ActionNone,
+		},
+		{
+			name:    "force uses default policy",
+			server:  config.Server{Name: "db", Enabled: true},
+			history: failures("db", now, 0, time.Minute, 2*time.Minute),
+			latest:  failing,
+			force:   true,
+			want:    ActionDisable,
+		},
+		{
+			name:   "cooldown not elapsed",
+			server: config.Server{Name: "db", Policy: auto, AutoDisabled: &config.AutoDisabled{At: now.Add(-time.Hour)}},
+			latest: passing,
+			want:   ActionNone,
+		},
+		{
+			name:   "cooldown elapsed but failing",
+			server: config.Server{Name: "db", Policy: auto, AutoDisabled: &config.AutoDisabled{At: now.Add(-3 * time.Hour)}},
+			latest: failing,
+			want:   ActionNone,
+		},
+		{
+			name:   "cooldown elapsed and passing",
+			server: config.Server{Name: "db", Policy: auto, AutoDisabled: &config.AutoDisabled{At: now.Add(-3 * time.Hour)}},
+			latest: passing,
+			want:   ActionEnable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Evaluate(tt.server, tt.history, tt.latest, now, tt.force)
+			if d.Action != tt.want {
+				t.Errorf("action = %s (%s), want %s", d.Action, d.Reason, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,477 @@
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"mseep/internal/config"
+	"mseep/internal/launchenv"
+)
+
+// DefaultTimeout bounds each helper command preflight runs
+const DefaultTimeout = 5 * time.Second
+
+// Status is the outcome of a single preflight check
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Check is one preflight finding for a server
+type Check struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// Report collects the preflight checks for one server in one client's
+// launch environment
+type Report struct {
+	Server   string  `json:"server"`
+	Client   string  `json:"client"`
+	Command  string  `json:"command,omitempty"`
+	Resolved string  `json:"resolved,omitempty"`
+	Checks   []Check `json:"checks"`
+}
+
+// Failed reports whether any check failed, meaning the client cannot
+// start the server as configured
+func (r Report) Failed() bool {
+	for _, c := range r.Checks {
+		if c.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Problems returns the checks that did not pass
+func (r Report) Problems() []Check {
+	var out []Check
+	for _, c := range r.Checks {
+		if c.Status != StatusOK {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// runtimeSpec describes the interpreter behind a launcher command
+type runtimeSpec struct {
+	name    string   // display name
+	binary  string   // executable whose version is checked
+	args    []string // arguments that print the version
+	minimum []int    // minimum major/minor; nil for no requirement
+	install string
+}
+
+var runtimes = map[string]runtimeSpec{
+	"node":   {name: "Node.js", binary: "node", args: []string{"--version"}, minimum: []int{18}, install: "Install Node.js 18 or newer from https://nodejs.org"},
+	"python": {name: "Python", binary: "", args: []string{"--version"}, minimum: []int{3, 10}, install: "Install Python 3.10 or newer"},
+	"uv":     {name: "uv", binary: "uv", args: []string{"--version"}, install: "Install uv: curl -LsSf https://astral.sh/uv/install.sh | sh"},
+	"docker": {name: "Docker", binary: "docker", args: []string{"info", "--format", "{{.ServerVersion}}"}, install: "Start Docker Desktop or the Docker daemon"},
+}
+
+// runtimeFor maps a launcher command to its runtime
+func runtimeFor(command string) (string, bool) {
+	switch commandBase(command) {
+	case "npx", "npm", "node":
+		return "node", true
+	case "python", "python3":
+		return "python", true
+	case "uvx", "uv":
+		return "uv", true
+	case "docker":
+		return "docker", true
+	}
+	return "", false
+}
+
+// commandBase strips the directory and Windows executable suffixes
+func commandBase(command string) string {
+	base := strings.ToLower(filepath.Base(command))
+	for _, ext := range []string{".exe", ".cmd", ".bat"} {
+		base = strings.TrimSuffix(base, ext)
+	}
+	return base
+}
+
+// Runner runs preflight checks in one client's launch environment. Runtime
+// versions are cached so checking many servers probes each runtime once.
+type Runner struct {
+	Env     *launchenv.Env
+	Timeout time.Duration
+
+	versions map[string]Check
+}
+
+// New returns a Runner for env
+func New(env *launchenv.Env) *Runner {
+	return &Runner{Env: env, Timeout: DefaultTimeout, versions: map[string]Check{}}
+}
+
+// Applies reports whether server is launched locally and so has
+// something to preflight
+func Applies(server config.Server) bool {
+	if server.Transport != "" && server.Transport != "stdio" {
+		return false
+	}
+	if server.Health != nil && (server.Health.Type == "mcp-http" || server.Health.Type == "mcp-sse") {
+		return false
+	}
+	return true
+}
+
+// Run checks that server's command resolves, its runtime is usable and its
+// package is available offline
+func (r *Runner) Run(ctx context.Context, server config.Server) Report {
+	rep := Report{Server: server.Name, Client: r.Env.Client, Command: server.Command}
+	if !Applies(server) {
+		return rep
+	}
+	if server.Command == "" {
+		rep.Checks = append(rep.Checks, Check{Name: "command", Status: StatusFail, Detail: "no command configured", Fix: "Set \"command\" in canonical.json"})
+		return rep
+	}
+
+	// Server env may extend PATH, as it does when the client launches it
+	env := r.Env.ForServer(server.Env)
+
+	resolved, err := env.LookPath(server.Command)
+	if err != nil {
+		rep.Checks = append(rep.Checks, r.missingCommand(server.Command, env))
+		return rep
+	}
+	rep.Resolved = resolved
+	rep.Checks = append(rep.Checks, Check{Name: "command", Status: StatusOK, Detail: fmt.Sprintf("%s resolves to %s", server.Command, resolved)})
+
+	rt, ok := runtimeFor(server.Command)
+	if !ok {
+		return rep
+	}
+	version := r.runtimeVersion(ctx, rt, resolved, env)
+	rep.Checks = append(rep.Checks, version)
+	if version.Status == StatusFail {
+		return rep
+	}
+
+	switch commandBase(server.Command) {
+	case "npx":
+		if pkg := NpxPackage(server.Args); pkg != "" {
+			rep.Checks = append(rep.Checks, r.npxCached(ctx, pkg, env))
+		}
+	case "uvx":
+		if pkg := UvxPackage(server.Args); pkg != "" {
+			rep.Checks = append(rep.Checks, r.uvxCached(ctx, pkg, env))
+		}
+	case "docker":
+		if image := DockerImage(server.Args); image != "" {
+			rep.Checks = append(rep.Checks, r.dockerImage(ctx, image, resolved, env))
+		}
+	}
+	return rep
+}
+
+// missingCommand explains why command is not on the client's PATH,
+// pointing at the shell's copy when only the client cannot see it
+func (r *Runner) missingCommand(command string, env *launchenv.Env) Check {
+	c := Check{Name: "command", Status: StatusFail, Detail: fmt.Sprintf("%s not found on %s PATH", command, env.Name)}
+	if shellPath, err := exec.LookPath(command); err == nil && env.Minimal {
+		c.Detail = fmt.Sprintf("%s is on your shell PATH but %s does not load your shell profile", command, env.Name)
+		c.Fix = fmt.Sprintf("Set \"command\" to %s, or add a PATH entry to the server's env", shellPath)
+		return c
+	}
+	if rt, ok := runtimeFor(command); ok {
+		c.Fix = runtimes[rt].install
+	} else {
+		c.Fix = fmt.Sprintf("Install %s or set \"command\" to its absolute path", command)
+	}
+	return c
+}
+
+// runtimeVersion checks the runtime behind a launcher, caching by runtime
+func (r *Runner) runtimeVersion(ctx context.Context, rt, resolved string, env *launchenv.Env) Check {
+	spec := runtimes[rt]
+	binary := spec.binary
+	if binary == "" {
+		// python is its own runtime
+		binary = resolved
+	}
+	key := rt + "\x00" + binary + "\x00" + env.Getenv("PATH")
+	if c, ok := r.versions[key]; ok {
+		return c
+	}
+
+	c := Check{Name: "runtime", Status: StatusOK}
+	path, err := env.LookPath(binary)
+	if err != nil {
+		c.Status = StatusFail
+		c.Detail = fmt.Sprintf("%s not found on %s PATH", spec.name, env.Name)
+		c.Fix = spec.install
+	} else if out, err := r.output(ctx, env, path, spec.args...); err != nil {
+		c.Status = StatusFail
+		c.Detail = fmt.Sprintf("%s not usable: %s", spec.name, firstLine(out, err))
+		c.Fix = spec.install
+	} else {
+		version := parseVersion(out)
+		found := firstLine(out, nil)
+		if version != nil {
+			found = joinVersion(version)
+		}
+		c.Detail = fmt.Sprintf("%s %s", spec.name, found)
+		if spec.minimum != nil && version != nil && versionLess(version, spec.minimum) {
+			c.Status = StatusFail
+			c.Detail = fmt.Sprintf("%s %s is older than the required %s", spec.name, found, joinVersion(spec.minimum))
+			c.Fix = spec.install
+		}
+	}
+	r.versions[key] = c
+	return c
+}
+
+// npxCached looks for pkg in the npx cache and the global node_modules
+func (r *Runner) npxCached(ctx context.Context, pkg string, env *launchenv.Env) Check {
+	c := Check{Name: "package", Status: StatusOK, Detail: fmt.Sprintf("%s is cached", pkg)}
+
+	cacheDir := env.Getenv("npm_config_cache")
+	if cacheDir == "" {
+		if home := env.Getenv("HOME"); home != "" {
+			cacheDir = filepath.Join(home, ".npm")
+		}
+		if local := env.Getenv("LOCALAPPDATA"); runtime.GOOS == "windows" && local != "" {
+			cacheDir = filepath.Join(local, "npm-cache")
+		}
+	}
+	if cacheDir != "" {
+		if m, _ := filepath.Glob(filepath.Join(cacheDir, "_npx", "*", "node_modules", filepath.FromSlash(pkg), "package.json")); len(m) > 0 {
+			return c
+		}
+	}
+	if npm, err := env.LookPath("npm"); err == nil {
+		if root, err := r.output(ctx, env, npm, "root", "-g"); err == nil {
+			if _, err := os.Stat(filepath.Join(strings.TrimSpace(root), filepath.FromSlash(pkg), "package.json")); err == nil {
+				c.Detail = fmt.Sprintf("%s is installed globally", pkg)
+				return c
+			}
+		}
+	}
+
+	c.Status = StatusWarn
+	c.Detail = fmt.Sprintf("%s is not in the npx cache; the first launch downloads it and may exceed the client's startup timeout", pkg)
+	c.Fix = fmt.Sprintf("Warm the cache with: npx -y %s --help", pkg)
+	return c
+}
+
+// uvxCached looks for pkg among installed uv tools and unpacked archives
+// in the uv cache
+func (r *Runner) uvxCached(ctx context.Context, pkg string, env *launchenv.Env) Check {
+	c := Check{Name: "package", Status: StatusOK, Detail: fmt.Sprintf("%s is cached", pkg)}
+	uv, err := env.LookPath("uv")
+	if err != nil {
+		return c
+	}
+	norm := strings.ToLower(strings.NewReplacer("-", "_", ".", "_").Replace(pkg))
+
+	if dir, err := r.output(ctx, env, uv, "tool", "dir"); err == nil {
+		if _, err := os.Stat(filepath.Join(strings.TrimSpace(dir), pkg)); err == nil {
+			c.Detail = fmt.Sprintf("%s is installed as a uv tool", pkg)
+			return c
+		}
+	}
+	if dir, err := r.output(ctx, env, uv, "cache", "dir"); err == nil {
+		if m, _ := filepath.Glob(filepath.Join(strings.TrimSpace(dir), "archive-v*", "*", norm+"-*.dist-info")); len(m) > 0 {
+			return c
+		}
+	}
+
+	c.Status = StatusWarn
+	c.Detail = fmt.Sprintf("%s is not in the uv cache; the first launch downloads it", pkg)
+	c.Fix = fmt.Sprintf("Warm the cache with: uvx %s --help", pkg)
+	return c
+}
+
+// dockerImage checks that image has been pulled
+func (r *Runner) dockerImage(ctx context.Context, image, docker string, env *launchenv.Env) Check {
+	if _, err := r.output(ctx, env, docker, "image", "inspect", "--format", "{{.Id}}", image); err != nil {
+		return Check{
+			Name:   "package",
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("image %s is not pulled; the first launch pulls it", image),
+			Fix:    fmt.Sprintf("docker pull %s", image),
+		}
+	}
+	return Check{Name: "package", Status: StatusOK, Detail: fmt.Sprintf("image %s is present", image)}
+}
+
+// output runs a helper command in env and returns its combined output
+func (r *Runner) output(ctx context.Context, env *launchenv.Env, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = env.Vars
+	var buf bytes.Buffer
+	cmd.Stdout = &buf
+	cmd.Stderr = &buf
+	err := cmd.Run()
+	return buf.String(), err
+}
+
+// NpxPackage returns the package npx runs, without its version
+func NpxPackage(args []string) string {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "-p" || a == "--package":
+			if i+1 < len(args) {
+				return stripVersion(args[i+1])
+			}
+			return ""
+		case strings.HasPrefix(a, "--package="):
+			return stripVersion(strings.TrimPrefix(a, "--package="))
+		case a == "--":
+			continue
+		case strings.HasPrefix(a, "-"):
+			continue
+		default:
+			return stripVersion(a)
+		}
+	}
+	return ""
+}
+
+// stripVersion turns "@scope/name@1.2" or "name@latest" into the bare name
+func stripVersion(spec string) string {
+	if i := strings.LastIndex(spec, "@"); i > 0 {
+		return spec[:i]
+	}
+	return spec
+}
+
+// uvxValueFlags take a separate value argument
+var uvxValueFlags = map[string]bool{
+	"--with": true, "--python": true, "-p": true,
+	"--index": true, "--index-url": true, "--extra-index-url": true, "--with-requirements": true,
+}
+
+// UvxPackage returns the package uvx runs, honoring --from
+func UvxPackage(args []string) string {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--from" {
+			if i+1 < len(args) {
+				return stripSpecifier(args[i+1])
+			}
+			return ""
+		}
+		if strings.HasPrefix(a, "--from=") {
+			return stripSpecifier(strings.TrimPrefix(a, "--from="))
+		}
+		if strings.HasPrefix(a, "-") {
+			if uvxValueFlags[a] {
+				i++
+			}
+			continue
+		}
+		return stripSpecifier(a)
+	}
+	return ""
+}
+
+// stripSpecifier drops extras and version specifiers from a Python requirement
+func stripSpecifier(req string) string {
+	if i := strings.IndexAny(req, "[=<>~!@"); i > 0 {
+		return req[:i]
+	}
+	return req
+}
+
+// dockerValueFlags take a separate value argument in 'docker run'
+var dockerValueFlags = map[string]bool{
+	"-e": true, "--env": true, "--env-file": true, "-v": true, "--volume": true,
+	"--name": true, "--network": true, "--net": true, "-p": true, "--publish": true,
+	"-w": true, "--workdir": true, "--entrypoint": true, "-u": true, "--user": true,
+	"--mount": true, "--platform": true, "-l": true, "--label": true, "--add-host": true,
+	"--cpus": true, "-m": true, "--memory": true, "--pull": true,
+}
+
+// DockerImage returns the image a 'docker run' invocation starts
+func DockerImage(args []string) string {
+	i := 0
+	for i < len(args) && args[i] != "run" {
+		i++
+	}
+	for i++; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "-") {
+			return a
+		}
+		if dockerValueFlags[a] {
+			i++
+		}
+	}
+	return ""
+}
+
+var versionRE = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// parseVersion extracts the first dotted version number from s
+func parseVersion(s string) []int {
+	m := versionRE.FindStringSubmatch(s)
+	if m == nil {
+		return nil
+	}
+	var v []int
+	for _, part := range m[1:] {
+		if part == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(part)
+		v = append(v, n)
+	}
+	return v
+}
+
+// versionLess compares v against a minimum, component by component
+func versionLess(v, min []int) bool {
+	for i, m := range min {
+		if i >= len(v) {
+			return false
+		}
+		if v[i] != m {
+			return v[i] < m
+		}
+	}
+	return false
+}
+
+func joinVersion(v []int) string {
+	parts := make([]string, len(v))
+	for i, n := range v {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// firstLine summarizes a failed helper command
+func firstLine(out string, err error) string {
+	if line, _, _ := strings.Cut(strings.TrimSpace(out), "\n"); line != "" {
+		return line
+	}
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,88 @@
+package preflight
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"mseep/internal/config"
+	"mseep/internal/launchenv"
+)
+
+func TestPackageParsing(t *testing.T) {
+	npx := map[string][]string{
+		"@modelcontextprotocol/server-github": {"-y", "@modelcontextprotocol/server-github"},
+		"@scope/pkg":                          {"--yes", "@scope/pkg@1.2.3", "--port", "3"},
+		"mcp-remote":                          {"-p", "mcp-remote@latest", "mcp-remote", "https://x"},
+		"other":                               {"--package=other", "bin"},
+	}
+	for want, args := range npx {
+		if got := NpxPackage(args); got != want {
+			t.Errorf("NpxPackage(%v) = %q, want %q", args, got, want)
+		}
+	}
+
+	uvx := map[string][]string{
+		"mcp-server-fetch": {"mcp-server-fetch"},
+		"mcp-server-git":   {"--python", "3.12", "mcp-server-git==0.6.2", "--repository", "."},
+		"mcp-proxy":        {"--from", "mcp-proxy[sse]", "mcp-proxy"},
+	}
+	for want, args := range uvx {
+		if got := UvxPackage(args); got != want {
+			t.Errorf("UvxPackage(%v) = %q, want %q", args, got, want)
+		}
+	}
+
+	docker := []string{"run", "-i", "--rm", "-e", "GITHUB_TOKEN", "--network=host", "ghcr.io/github/github-mcp-server", "stdio"}
+	if got := DockerImage(docker); got != "ghcr.io/github/github-mcp-server" {
+		t.Errorf("DockerImage() = %q", got)
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	tests := []struct {
+		out  string
+		min  []int
+		less bool
+	}{
+		{"v20.11.1", []int{18}, false},
+		{"v16.20.0", []int{18}, true},
+		{"Python 3.9.18", []int{3, 10}, true},
+		{"Python 3.12.0", []int{3, 10}, false},
+	}
+	for _, tt := range tests {
+		if got := versionLess(parseVersion(tt.out), tt.min); got != tt.less {
+			t.Errorf("versionLess(%q, %v) = %v, want %v", tt.out, tt.min, got, tt.less)
+		}
+	}
+}
+
+func TestRunMissingCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "my-mcp")
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	env := &launchenv.Env{Client: "claude", Name: "Claude Desktop", Minimal: true, Vars: []string{"PATH=" + launchenv.MinimalPath}}
+	r := New(env)
+
+	rep := r.Run(context.Background(), config.Server{Name: "x", Command: "my-mcp"})
+	if !rep.Failed() {
+		t.Fatalf("expected failure for command outside the client PATH: %+v", rep)
+	}
+
+	// The server's own env can extend PATH
+	rep = r.Run(context.Background(), config.Server{Name: "x", Command: "my-mcp", Env: map[string]string{"PATH": dir}})
+	if rep.Failed() || rep.Resolved != tool {
+		t.Fatalf("expected %s to resolve via server PATH: %+v", tool, rep)
+	}
+
+	if rep := r.Run(context.Background(), config.Server{Name: "r", Transport: "http"}); len(rep.Checks) != 0 {
+		t.Errorf("remote server should have nothing to preflight: %+v", rep)
+	}
+}
@@ -0,0 +1,228 @@
+package project
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"mseep/internal/config"
+)
+
+// MarkerFile is the name of the file that marks a project root
+const MarkerFile = ".mseep.json"
+
+// DefaultClients are the clients that read project-scoped MCP configs
+var DefaultClients = []string{"cursor", "vscode"}
+
+// Marker is the contents of a .mseep.json file: a profile or a server
+// list, and the clients whose project config should receive it
+type Marker struct {
+	Profile string   `json:"profile,omitempty"`
+	Servers []string `json:"servers,omitempty"`
+	Clients []string `json:"clients,omitempty"`
+}
+
+// Find returns the directory of the nearest marker at or above dir and
+// its contents. It returns an empty root and no error when there is none.
+func Find(dir string) (string, *Marker, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	for {
+		p := filepath.Join(dir, MarkerFile)
+		b, err := os.ReadFile(p)
+		if err == nil {
+			var m Marker
+			if err := json.Unmarshal(b, &m); err != nil {
+				return "", nil, fmt.Errorf("invalid %s: %w", p, err)
+			}
+			if m.Profile == "" && len(m.Servers) == 0 {
+				return "", nil, fmt.Errorf("%s names neither a profile nor servers", p)
+			}
+			if len(m.Clients) == 0 {
+				m.Clients = DefaultClients
+			}
+			return dir, &m, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", nil, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil, nil
+		}
+		dir = parent
+	}
+}
+
+// ConfigPath returns the project-scoped MCP config of client under root
+func ConfigPath(root, client string) (string, error) {
+	switch client {
+	case "cursor":
+		return filepath.Join(root, ".cursor", "mcp.json"), nil
+	case "vscode":
+		return filepath.Join(root, ".vscode", "mcp.json"), nil
+	}
+	return "", fmt.Errorf("%s has no project-scoped config (want %v)", client, DefaultClients)
+}
+
+// serversKey is the top-level key holding servers in client's project config
+func serversKey(client string) string {
+	if client == "vscode" {
+		return "servers"
+	}
+	return "mcpServers"
+}
+
+// Render returns client's project config with servers set as the managed
+// entries. Entries in existing that are not canonical servers, and other
+// top-level keys, are kept.
+func Render(client string, existing []byte, servers []config.Server, canon *config.Canonical) ([]byte, error) {
+	doc := map[string]interface{}{}
+	if len(bytes.TrimSpace(existing)) > 0 {
+		if err := json.Unmarshal(existing, &doc); err != nil {
+			return nil, fmt.Errorf("invalid existing config: %w", err)
+		}
+	}
+
+	key := serversKey(client)
+	entries := map[string]interface{}{}
+	if current, ok := doc[key].(map[string]interface{}); ok {
+		for name, v := range current {
+			if canon.FindByName(name) == nil {
+				entries[name] = v
+			}
+		}
+	}
+	for _, srv := range servers {
+		entry := map[string]interface{}{"command": srv.Command}
+		if client == "vscode" {
+			entry["type"] = "stdio"
+		}
+		if len(srv.Args) > 0 {
+			entry["args"] = srv.Args
+		}
+		if len(srv.Env) > 0 {
+			entry["env"] = srv.Env
+		}
+		entries[srv.Name] = entry
+	}
+	doc[key] = entries
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// FileBackup is a project config as it was before mseep first wrote it
+type FileBackup struct {
+	Path    string `json:"path"`
+	Existed bool   `json:"existed"`
+	Content []byte `json:"content,omitempty"`
+}
+
+// State records the project whose configs mseep last applied, so they can
+// be restored when the shell leaves it
+type State struct {
+	Root    string       `json:"root"`
+	Servers []string     `json:"servers"`
+	Files   []FileBackup `json:"files"`
+}
+
+// StatePath returns the location of the active project state
+func StatePath() (string, error) {
+	dir, err := config.EnsureDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "project.json"), nil
+}
+
+// LoadState returns the active project, or nil when none is applied
+func LoadState() (*State, error) {
+	p, err := StatePath()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("invalid project state %s: %w", p, err)
+	}
+	return &s, nil
+}
+
+// SaveState records s as the active project; nil clears it
+func SaveState(s *State) error {
+	p, err := StatePath()
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, b, 0o644)
+}
+
+// Backup snapshots path for later restore
+func Backup(path string) (FileBackup, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return FileBackup{Path: path}, nil
+	}
+	if err != nil {
+		return FileBackup{}, err
+	}
+	return FileBackup{Path: path, Existed: true, Content: b}, nil
+}
+
+// Restore puts every file back as it was before it was first written,
+// removing files and directories mseep created
+func (s *State) Restore() error {
+	var errs []error
+	for _, f := range s.Files {
+		if f.Existed {
+			if err := os.WriteFile(f.Path, f.Content, 0o644); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if err := os.Remove(f.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		// Only succeeds when mseep's file was the directory's only entry
+		os.Remove(filepath.Dir(f.Path))
+	}
+	return errors.Join(errs...)
+}
+
+// SortedNames returns the names of servers, sorted
+func SortedNames(servers []config.Server) []string {
+	names := make([]string, len(servers))
+	for i, s := range servers {
+		names[i] = s.Name
+	}
+	sort.Strings(names)
+	return names
+}
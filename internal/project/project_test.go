@@ -0,0 +1,91 @@
+package project
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mseep/internal/config"
+)
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	deep := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, MarkerFile), []byte(`{"profile":"security"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, m, err := Find(deep)
+	if err != nil || got != root {
+		t.Fatalf("Find() = %q, %v, want %q", got, err, root)
+	}
+	if m.Profile != "security" || len(m.Clients) != len(DefaultClients) {
+		t.Errorf("marker = %+v, want profile security with default clients", m)
+	}
+
+	if got, _, err := Find(t.TempDir()); err != nil || got != "" {
+		t.Errorf("Find() outside a project = %q, %v", got, err)
+	}
+}
+
+func TestRenderKeepsUnmanaged(t *testing.T) {
+	canon := &config.Canonical{Servers: []config.Server{
+		{Name: "burp", Command: "burp-mcp"},
+		{Name: "old", Command: "old-mcp"},
+	}}
+	existing := []byte(`{"inputs":[],"servers":{"mine":{"type":"http","url":"x"},"old":{"command":"old-mcp"}}}`)
+
+	b, err := Render("vscode", existing, canon.Servers[:1], canon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Inputs  []interface{}                     `json:"inputs"`
+		Servers map[string]map[string]interface{} `json:"servers"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Inputs == nil || doc.Servers["mine"] == nil {
+		t.Errorf("unmanaged settings dropped: %s", b)
+	}
+	if doc.Servers["old"] != nil {
+		t.Errorf("canonical server not in the set was kept: %s", b)
+	}
+	if doc.Servers["burp"]["type"] != "stdio" || doc.Servers["burp"]["command"] != "burp-mcp" {
+		t.Errorf("burp entry = %v", doc.Servers["burp"])
+	}
+}
+
+func TestRestore(t *testing.T) {
+	root := t.TempDir()
+	kept := filepath.Join(root, "kept.json")
+	os.WriteFile(kept, []byte("original"), 0o644)
+	created := filepath.Join(root, ".cursor", "mcp.json")
+
+	var s State
+	for _, p := range []string{kept, created} {
+		b, err := Backup(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Files = append(s.Files, b)
+	}
+	os.MkdirAll(filepath.Dir(created), 0o755)
+	os.WriteFile(created, []byte("{}"), 0o644)
+	os.WriteFile(kept, []byte("changed"), 0o644)
+
+	if err := s.Restore(); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(kept); string(b) != "original" {
+		t.Errorf("kept.json = %q, want original", b)
+	}
+	if _, err := os.Stat(filepath.Dir(created)); !os.IsNotExist(err) {
+		t.Errorf(".cursor should be removed, stat err = %v", err)
+	}
+}
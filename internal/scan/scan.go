@@ -0,0 +1,258 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"mseep/internal/inventory"
+	"mseep/internal/mcp"
+)
+
+// Severity ranks how suspicious a finding is
+type Severity string
+
+const (
+	SeverityHigh   Severity = "high"
+	SeverityMedium Severity = "medium"
+	SeverityLow    Severity = "low"
+)
+
+// Finding is one suspicious property of a tool definition
+type Finding struct {
+	Server   string   `json:"server"`
+	Tool     string   `json:"tool"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Evidence string   `json:"evidence,omitempty"`
+}
+
+// Rule describes a check the scanner performs
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+}
+
+// Rules lists every rule, in the order findings are reported
+var Rules = []Rule{
+	{"hidden-instructions", SeverityHigh, "Tool text contains instructions aimed at the model rather than describing the tool"},
+	{"invisible-unicode", SeverityHigh, "Tool text contains invisible or direction-changing Unicode characters"},
+	{"tool-shadowing", SeverityHigh, "Tool name is also exposed by another enabled server"},
+	{"cross-server-reference", SeverityMedium, "Tool text refers to another server or its tools"},
+	{"excessive-length", SeverityLow, "Tool description is unusually long"},
+}
+
+// MaxDescriptionLength is the description length above which a tool is flagged
+const MaxDescriptionLength = 1024
+
+// instructionPatterns match phrasing typical of prompt injection in tool text
+var instructionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)ignore\s+(all\s+)?(previous|prior|above|earlier)`),
+	regexp.MustCompile(`(?i)disregard\s+(all\s+)?(previous|prior|above|earlier|your)`),
+	regexp.MustCompile(`(?i)(do\s+not|don'?t|never)\s+(tell|inform|mention|reveal|notify|show)\b.{0,20}\b(user|human)`),
+	regexp.MustCompile(`(?i)without\s+(telling|informing|notifying)\s+the\s+(user|human)`),
+	regexp.MustCompile(`(?i)<\s*/?\s*(important|system|instructions?|secret|hidden)\s*>`),
+	regexp.MustCompile(`(?i)\b(you\s+must|you\s+should)\s+(first\s+)?(read|send|call|include|pass|upload)\b`),
+	regexp.MustCompile(`(?i)(~/\.ssh|id_rsa|mcp\.json)`),
+	regexp.MustCompile(`(?i)system\s+prompt`),
+}
+
+// isInvisible reports zero-width, bidi control, tag and other format runes
+func isInvisible(r rune) bool {
+	switch {
+	case r >= 0xE0000 && r <= 0xE007F: // tag characters
+		return true
+	case r == '\u034F', r == '\u115F', r == '\u1160', r == '\u3164', r == '\uFFA0': // joiners and fillers outside Cf
+		return true
+	case r == '\n', r == '\r', r == '\t':
+		return false
+	}
+	return unicode.Is(unicode.Cf, r)
+}
+
+// Scan checks the tool definitions of all inventories. Inventories are
+// assumed to be the enabled servers, so shadowing compares across them.
+func Scan(invs []*inventory.Inventory) []Finding {
+	var findings []Finding
+
+	// Index tool names by server for shadowing and cross-references
+	owners := map[string][]string{}
+	for _, inv := range invs {
+		for _, t := range inv.Tools {
+			owners[t.Name] = append(owners[t.Name], inv.Server)
+		}
+	}
+
+	for _, inv := range invs {
+		for _, t := range inv.Tools {
+			text := toolText(t)
+			add := func(rule, msg, evidence string) {
+				findings = append(findings, Finding{
+					Server:   inv.Server,
+					Tool:     t.Name,
+					Rule:     rule,
+					Severity: severity(rule),
+					Message:  msg,
+					Evidence: evidence,
+				})
+			}
+
+			for _, re := range instructionPatterns {
+				if m := re.FindString(text); m != "" {
+					add("hidden-instructions", "text reads like instructions to the model", m)
+					break
+				}
+			}
+
+			if runes := invisibleRunes(text); len(runes) > 0 {
+				add("invisible-unicode", fmt.Sprintf("%d invisible characters", len(runes)), strings.Join(runes, " "))
+			}
+
+			if others := otherServers(owners[t.Name], inv.Server); len(others) > 0 {
+				add("tool-shadowing", fmt.Sprintf("tool name also exposed by %s", strings.Join(others, ", ")), t.Name)
+			}
+
+			if ref := crossReference(text, inv, invs); ref != "" {
+				add("cross-server-reference", "text mentions another server's tools", ref)
+			}
+
+			if n := len([]rune(t.Description)); n > MaxDescriptionLength {
+				add("excessive-length", fmt.Sprintf("description is %d characters (limit %d)", n, MaxDescriptionLength), "")
+			}
+		}
+	}
+
+	order := map[string]int{}
+	for i, r := range Rules {
+		order[r.ID] = i
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if order[findings[i].Rule] != order[findings[j].Rule] {
+			return order[findings[i].Rule] < order[findings[j].Rule]
+		}
+		if findings[i].Server != findings[j].Server {
+			return findings[i].Server < findings[j].Server
+		}
+		return findings[i].Tool < findings[j].Tool
+	})
+	return findings
+}
+
+func severity(rule string) Severity {
+	for _, r := range Rules {
+		if r.ID == rule {
+			return r.Severity
+		}
+	}
+	return SeverityLow
+}
+
+// toolText is all model-visible text of a tool: title, description and the
+// input schema, whose property descriptions can also carry instructions
+func toolText(t mcp.Tool) string {
+	parts := []string{t.Title, t.Description}
+	var schema interface{}
+	if json.Unmarshal(t.InputSchema, &schema) == nil {
+		parts = collectStrings(schema, parts)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// collectStrings appends every string value found in a decoded JSON value
+func collectStrings(v interface{}, out []string) []string {
+	switch v := v.(type) {
+	case string:
+		out = append(out, v)
+	case []interface{}:
+		for _, e := range v {
+			out = collectStrings(e, out)
+		}
+	case map[string]interface{}:
+		for _, e := range v {
+			out = collectStrings(e, out)
+		}
+	}
+	return out
+}
+
+func invisibleRunes(s string) []string {
+	var found []string
+	for _, r := range s {
+		if isInvisible(r) {
+			found = append(found, fmt.Sprintf("U+%04X", r))
+		}
+	}
+	return found
+}
+
+func otherServers(servers []string, self string) []string {
+	var others []string
+	seen := map[string]bool{}
+	for _, s := range servers {
+		if s != self && !seen[s] {
+			seen[s] = true
+			others = append(others, s)
+		}
+	}
+	return others
+}
+
+// crossReference finds a mention of another server's name or one of its
+// distinctive tool names (those containing '_' or '-', which are unlikely
+// to be ordinary words)
+func crossReference(text string, self *inventory.Inventory, invs []*inventory.Inventory) string {
+	lower := strings.ToLower(text)
+	for _, other := range invs {
+		if other.Server == self.Server {
+			continue
+		}
+		if len(other.Server) >= 3 && containsWord(lower, strings.ToLower(other.Server)) {
+			return other.Server
+		}
+		for _, t := range other.Tools {
+			if !strings.ContainsAny(t.Name, "_-") || hasTool(self, t.Name) {
+				continue
+			}
+			if containsWord(lower, strings.ToLower(t.Name)) {
+				return fmt.Sprintf("%s (%s)", t.Name, other.Server)
+			}
+		}
+	}
+	return ""
+}
+
+func hasTool(inv *inventory.Inventory, name string) bool {
+	for _, t := range inv.Tools {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// containsWord reports whether word occurs in s bounded by non-identifier runes
+func containsWord(s, word string) bool {
+	isIdent := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' }
+	for start := 0; ; {
+		i := strings.Index(s[start:], word)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(word)
+		prev, _ := utf8.DecodeLastRuneInString(s[:i])
+		next, _ := utf8.DecodeRuneInString(s[end:])
+		before := i == 0 || !isIdent(prev)
+		after := end == len(s) || !isIdent(next)
+		if before && after {
+			return true
+		}
+		start = i + 1
+	}
+}
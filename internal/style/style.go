@@ -90,6 +90,16 @@ func Error(text string) string {
 	return errorStyle.Render("✗ " + text)
 }
 
+// Added renders an added line of a list diff
+func Added(text string) string {
+	return successStyle.Render("+ " + text)
+}
+
+// Removed renders a removed line of a list diff
+func Removed(text string) string {
+	return errorStyle.Render("- " + text)
+}
+
 // Muted renders muted/secondary text
 func Muted(text string) string {
 	return mutedStyle.Render(text)
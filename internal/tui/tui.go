@@ -255,7 +255,7 @@ type serverItem struct {
 func (i serverItem) Title() string {
 	icon := "○"
 	style := disabledStyle
-	if i.Enabled {
+	if i.EnabledAnywhere() {
 		icon = "●"
 		style = enabledStyle
 	}
@@ -372,6 +372,8 @@ type Model struct {
 	marketplace       *marketplace.Marketplace
 	marketplaceServers []marketplace.ServerEntry
 	healthResults     []health.CheckResult
+	healthCursor      int
+	healthLogs        map[string][]app.ClientLog
 	width             int
 	height            int
 	showHelp          bool
@@ -494,6 +496,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.showHelp = !m.showHelp
 			return m, nil
 
+		case m.mode == viewHealth && key.Matches(msg, keys.Up):
+			if m.healthCursor > 0 {
+				m.healthCursor--
+			}
+			return m, nil
+
+		case m.mode == viewHealth && key.Matches(msg, keys.Down):
+			if m.healthCursor < len(m.healthResults)-1 {
+				m.healthCursor++
+			}
+			return m, nil
+
 		case key.Matches(msg, keys.Tab), key.Matches(msg, keys.Right):
 			m.mode = (m.mode + 1) % 5
 			return m, nil
@@ -531,6 +545,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case healthCheckMsg:
 		m.loading = false
 		m.healthResults = msg.results
+		m.healthLogs = msg.logs
+		m.healthCursor = 0
 		m.mode = viewHealth
 		m.updateHealthView()
 		return m, nil
@@ -700,7 +716,7 @@ func (m *Model) renderServerView() string {
 	enabled := 0
 	disabled := 0
 	for _, srv := range m.app.Canon.Servers {
-		if srv.Enabled {
+		if srv.EnabledAnywhere() {
 			enabled++
 		} else {
 			disabled++
@@ -774,7 +790,7 @@ func (m *Model) renderHealthView() string {
 	
 	// Individual results
 	content.WriteString("📋 Detailed Results:\n\n")
-	for _, result := range m.healthResults {
+	for i, result := range m.healthResults {
 		icon := "❓"
 		var style lipgloss.Style
 		
@@ -791,6 +807,9 @@ func (m *Model) renderHealthView() string {
 		case health.StatusError:
 			icon = "⚠️"
 			style = lipgloss.NewStyle().Foreground(errorColor)
+		case health.StatusProtocolError:
+			icon = "🔌"
+			style = lipgloss.NewStyle().Foreground(errorColor)
 		}
 		
 		resultLine := fmt.Sprintf("%s %s - %s (%v)",
@@ -799,12 +818,49 @@ func (m *Model) renderHealthView() string {
 			result.Message,
 			result.Duration.Round(time.Millisecond))
 		
-		content.WriteString("  " + resultLine + "\n")
+		cursor := "  "
+		if i == m.healthCursor {
+			cursor = "▸ "
+		}
+		content.WriteString(cursor + resultLine + "\n")
+	}
+	
+	if m.healthCursor < len(m.healthResults) {
+		content.WriteString("\n" + m.renderLogPane(m.healthResults[m.healthCursor]))
 	}
 	
 	return content.String()
 }
 
+// renderLogPane shows the selected server's captured output and the tail
+// of its client logs
+func (m *Model) renderLogPane(result health.CheckResult) string {
+	const maxLines = 8
+	var lines []string
+	tail := func(label, text string) {
+		text = strings.TrimRight(text, "\n")
+		if text == "" {
+			return
+		}
+		out := strings.Split(text, "\n")
+		if len(out) > maxLines {
+			out = out[len(out)-maxLines:]
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(mutedColor).Render(label))
+		lines = append(lines, out...)
+	}
+	tail("stderr:", result.Stderr)
+	tail("stdout:", result.Stdout)
+	for _, cl := range m.healthLogs[result.ServerName] {
+		tail(cl.Client+" log:", strings.Join(cl.Lines, "\n"))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No output captured")
+	}
+	
+	return infoBoxStyle.Width(m.width - 8).Render("📜 Logs: " + result.ServerName + "\n\n" + strings.Join(lines, "\n"))
+}
+
 func (m *Model) renderApplyView() string {
 	var sections []string
 	
@@ -883,7 +939,7 @@ func (m *Model) renderStatusBar() string {
 		case viewServers:
 			enabled := 0
 			for _, srv := range m.app.Canon.Servers {
-				if srv.Enabled {
+				if srv.EnabledAnywhere() {
 					enabled++
 				}
 			}
@@ -900,7 +956,7 @@ func (m *Model) renderStatusBar() string {
 						healthy++
 					}
 				}
-				status = fmt.Sprintf("🏥 %d/%d healthy | ⌨️ h: recheck | r: refresh | q: quit",
+				status = fmt.Sprintf("🏥 %d/%d healthy | ⌨️ ↑/↓: logs | h: recheck | r: refresh | q: quit",
 					healthy, len(m.healthResults))
 			} else {
 				status = "🏥 Press 'h' to run health checks | ?: help | q: quit"
@@ -932,7 +988,7 @@ func (m *Model) toggleSelectedServer() tea.Cmd {
 	if item, ok := m.serverList.SelectedItem().(serverItem); ok {
 		for i := range m.app.Canon.Servers {
 			if m.app.Canon.Servers[i].Name == item.Name {
-				m.app.Canon.Servers[i].Enabled = !m.app.Canon.Servers[i].Enabled
+				m.app.Canon.Servers[i].SetEnabled("", !m.app.Canon.Servers[i].EnabledAnywhere())
 				config.Save("", m.app.Canon)
 				m.updateServerList()
 				break
@@ -969,13 +1025,24 @@ func (m *Model) runHealthCheck() tea.Cmd {
 		
 		servers := []config.Server{}
 		for _, srv := range m.app.Canon.Servers {
-			if srv.Enabled {
+			if srv.EnabledAnywhere() {
 				servers = append(servers, srv)
 			}
 		}
 		
 		results := mgr.CheckServers(ctx, servers)
-		return healthCheckMsg{results: results}
+		
+		// Client logs explain failures the check output does not
+		logs := map[string][]app.ClientLog{}
+		for _, r := range results {
+			if r.Status == health.StatusHealthy {
+				continue
+			}
+			if sl, err := m.app.ServerLogs(r.ServerName, 8); err == nil {
+				logs[r.ServerName] = sl.ClientLogs
+			}
+		}
+		return healthCheckMsg{results: results, logs: logs}
 	}
 }
 
@@ -1130,6 +1197,7 @@ func (i profileItem) FilterValue() string { return i.name }
 // Messages
 type healthCheckMsg struct {
 	results []health.CheckResult
+	logs    map[string][]app.ClientLog
 }
 
 type applyMsg struct {